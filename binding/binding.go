@@ -3,10 +3,36 @@ package binding
 import "net/http"
 
 var (
-	JSON     = jsonBinding{}
-	XML      = xmlBinding{}
-	QUERY    = queryBinding{}
-	PROTOBUF = protobufBinding{}
+	JSON           = jsonBinding{}
+	XML            = xmlBinding{}
+	QUERY          = queryBinding{}
+	FORM           = formBinding{}
+	FORM_POST      = formPostBinding{}
+	FORM_MULTIPART = formMultipartBinding{}
+	URI            = uriBinding{}
+	HEADER         = headerBinding{}
+	YAML           = yamlBinding{}
+	TOML           = tomlBinding{}
+	MSGPACK        = msgpackBinding{}
+	PROTOBUF       = protobufBinding{}
+	PROTOJSON      = protoJSONBinding{}
+)
+
+// check the bindings implement the interfaces
+var (
+	_ BindingBody = jsonBinding{}
+	_ BindingBody = xmlBinding{}
+	_ BindingBody = yamlBinding{}
+	_ BindingBody = tomlBinding{}
+	_ BindingBody = msgpackBinding{}
+	_ BindingBody = protobufBinding{}
+	_ BindingBody = protoJSONBinding{}
+	_ Binding     = queryBinding{}
+	_ Binding     = formBinding{}
+	_ Binding     = formPostBinding{}
+	_ Binding     = formMultipartBinding{}
+	_ Binding     = headerBinding{}
+	_ BindingUri  = uriBinding{}
 )
 
 // Binding bind request's data to any interface
@@ -20,3 +46,28 @@ type BindingBody interface {
 	Binding
 	BindBody([]byte, any) error
 }
+
+// BindingUri bind route parameters to any interface
+type BindingUri interface {
+	Name() string
+	BindUri(map[string][]string, any) error
+}
+
+// StructValidator is the minimal interface which needs to be implemented in
+// order for it to be used as the validator engine of the bindings.
+type StructValidator interface {
+	// ValidateStruct can receive any kind of type and it should never panic,
+	// even if the configuration is not right.
+	ValidateStruct(any) error
+}
+
+// Validator is the default validator which runs after every binding, it can
+// be replaced by a custom implementation or set to nil to disable validation.
+var Validator StructValidator = &defaultValidator{}
+
+func validate(obj any) error {
+	if Validator == nil {
+		return nil
+	}
+	return Validator.ValidateStruct(obj)
+}
@@ -0,0 +1,305 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FieldLevel contains everything a Rule needs to validate a field
+type FieldLevel struct {
+	// Field is the value being validated, pointers are already dereferenced
+	Field reflect.Value
+	// Parent is the struct which contains the field, used by cross-field rules
+	Parent reflect.Value
+	// Name is the struct field name
+	Name string
+	// Param is the parameter of the rule, e.g. "64" for max=64
+	Param string
+}
+
+// Rule reports whether the field satisfies the rule
+type Rule func(fl FieldLevel) bool
+
+// FieldError describes a field which failed a validation rule
+type FieldError struct {
+	Field string // path of the field, e.g. User.Items[0].Name
+	Tag   string // the failed rule, e.g. max
+	Param string // parameter of the rule, e.g. 64 for max=64
+	Value any    // actual value of the field
+}
+
+func (e *FieldError) Error() string {
+	rule := e.Tag
+	if e.Param != "" {
+		rule += "=" + e.Param
+	}
+	return fmt.Sprintf("field '%s' failed on the '%s' rule", e.Field, rule)
+}
+
+// ValidationErrors contains every field which failed validation
+type ValidationErrors []*FieldError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]Rule{}
+)
+
+// RegisterRule registers a custom rule which can be used in the binding tag,
+// a built-in rule with the same name is replaced.
+func RegisterRule(name string, rule Rule) {
+	if name == "" || rule == nil {
+		panic("binding: RegisterRule name and rule must not be empty")
+	}
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[name] = rule
+}
+
+func lookupRule(name string) (Rule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	rule, ok := rules[name]
+	return rule, ok
+}
+
+type defaultValidator struct {
+	cache sync.Map // map[reflect.Type][]*cachedField
+}
+
+var _ StructValidator = (*defaultValidator)(nil)
+
+// ValidateStruct validates a struct, a pointer to a struct or a slice of them
+// by the rules of the binding tag, e.g. `binding:"required,min=1,max=64"`.
+func (v *defaultValidator) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+
+	w := &walker{v: v}
+	value := reflect.ValueOf(obj)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		w.walkStruct("", value)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if el, ok := structValue(value.Index(i)); ok {
+				w.walkStruct("["+strconv.Itoa(i)+"]", el)
+			}
+		}
+	}
+
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.errs) > 0 {
+		return w.errs
+	}
+	return nil
+}
+
+// chain is the parsed binding tag, the rules after dive apply to the elements
+type chain struct {
+	omitEmpty bool
+	required  bool
+	rules     []ruleSpec
+	dive      *chain
+}
+
+type ruleSpec struct {
+	name  string
+	param string
+}
+
+type cachedField struct {
+	index     int
+	name      string
+	anonymous bool
+	chain     *chain
+}
+
+func (v *defaultValidator) fields(t reflect.Type) []*cachedField {
+	if fs, ok := v.cache.Load(t); ok {
+		return fs.([]*cachedField)
+	}
+
+	fs := make([]*cachedField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous { // unexported
+			continue
+		}
+		tag := sf.Tag.Get("binding")
+		if tag == "-" {
+			continue
+		}
+		fs = append(fs, &cachedField{index: i, name: sf.Name, anonymous: sf.Anonymous, chain: parseChain(tag)})
+	}
+	v.cache.Store(t, fs)
+	return fs
+}
+
+func parseChain(tag string) *chain {
+	root := &chain{}
+	cur := root
+	var opt string
+	for len(tag) > 0 {
+		opt, tag = head(tag, ",")
+		name, param := head(opt, "=")
+		switch name {
+		case "":
+			continue
+		case "omitempty":
+			cur.omitEmpty = true
+		case "required":
+			cur.required = true
+		case "dive":
+			cur.dive = &chain{}
+			cur = cur.dive
+		default:
+			cur.rules = append(cur.rules, ruleSpec{name: name, param: param})
+		}
+	}
+	return root
+}
+
+// walker walks through a struct and collects every failed field
+type walker struct {
+	v    *defaultValidator
+	errs ValidationErrors
+	err  error
+}
+
+func (w *walker) walkStruct(path string, value reflect.Value) {
+	for _, f := range w.v.fields(value.Type()) {
+		fpath := f.name
+		if path != "" {
+			fpath = path + "." + f.name
+		}
+		fv := value.Field(f.index)
+		if !f.anonymous {
+			w.walkValue(fpath, f.name, fv, value, f.chain)
+		} else if w.walkField(fpath, f.name, fv, value, f.chain) && w.err == nil {
+			// fields of embedded structs are promoted, the same as fieldPath of the form mapper
+			if embedded, ok := structValue(fv); ok {
+				w.walkStruct(path, embedded)
+			}
+		}
+		if w.err != nil {
+			return
+		}
+	}
+}
+
+// walkValue validates the value and the struct it points to
+func (w *walker) walkValue(path, name string, v, parent reflect.Value, c *chain) {
+	if !w.walkField(path, name, v, parent, c) || w.err != nil {
+		return
+	}
+	if nested, ok := structValue(v); ok {
+		w.walkStruct(path, nested)
+	}
+}
+
+// walkField applies the rules of c to the field and reports whether the
+// struct it holds should be validated as well. Nil pointers and empty
+// omitempty fields only fail the required rule.
+func (w *walker) walkField(path, name string, fv, parent reflect.Value, c *chain) bool {
+	field, isNil := indirect(fv)
+	if isNil || (c.omitEmpty && !hasValue(field)) {
+		if c.required {
+			w.errs = append(w.errs, &FieldError{Field: path, Tag: "required", Value: valueOf(field)})
+		}
+		return false
+	}
+
+	if c.required && !hasValue(field) {
+		w.errs = append(w.errs, &FieldError{Field: path, Tag: "required", Value: valueOf(field)})
+		return false
+	}
+
+	for _, r := range c.rules {
+		rule, ok := lookupRule(r.name)
+		if !ok {
+			w.err = fmt.Errorf("binding: undefined validation rule '%s' on field '%s'", r.name, path)
+			return false
+		}
+		if !rule(FieldLevel{Field: field, Parent: parent, Name: name, Param: r.param}) {
+			w.errs = append(w.errs, &FieldError{Field: path, Tag: r.name, Param: r.param, Value: valueOf(field)})
+			return false
+		}
+	}
+
+	if c.dive == nil {
+		return true
+	}
+
+	switch field.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < field.Len() && w.err == nil; i++ {
+			w.walkValue(path+"["+strconv.Itoa(i)+"]", name, field.Index(i), parent, c.dive)
+		}
+	case reflect.Map:
+		iter := field.MapRange()
+		for iter.Next() && w.err == nil {
+			w.walkValue(fmt.Sprintf("%s[%v]", path, iter.Key().Interface()), name, iter.Value(), parent, c.dive)
+		}
+	default:
+		w.err = fmt.Errorf("binding: dive on field '%s' of kind %s", path, field.Kind())
+	}
+	return false
+}
+
+// indirect dereferences pointers and interfaces, isNil is true if one of them is nil
+func indirect(v reflect.Value) (value reflect.Value, isNil bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, true
+		}
+		v = v.Elem()
+	}
+	return v, false
+}
+
+// structValue returns the struct which should be validated recursively
+func structValue(v reflect.Value) (reflect.Value, bool) {
+	v, isNil := indirect(v)
+	if isNil || v.Kind() != reflect.Struct {
+		return v, false
+	}
+	return v, v.Type() != reflect.TypeOf(time.Time{})
+}
+
+// hasValue reports whether the field is not the zero value of its type
+func hasValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
+		return !v.IsNil()
+	default:
+		return v.IsValid() && !v.IsZero()
+	}
+}
+
+func valueOf(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
@@ -0,0 +1,195 @@
+package binding
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type validateItem struct {
+	SKU string `json:"sku" binding:"required,alphanum"`
+	Qty int    `json:"qty" binding:"gte=1"`
+}
+
+type validateOrder struct {
+	Name     string            `json:"name" binding:"required,min=1,max=8"`
+	Email    string            `json:"email" binding:"omitempty,email"`
+	Status   string            `json:"status" binding:"oneof=new paid"`
+	Password string            `json:"password"`
+	Confirm  string            `json:"confirm" binding:"eqfield=Password"`
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end" binding:"gtfield=Start"`
+	Tags     []string          `json:"tags" binding:"max=3,dive,required,max=4"`
+	Items    []validateItem    `json:"items" binding:"required,dive"`
+	Meta     map[string]string `json:"meta" binding:"dive,alpha"`
+	Note     *string           `json:"note" binding:"max=4"`
+	Shipping *struct {
+		City string `json:"city" binding:"required"`
+	} `json:"shipping"`
+}
+
+func validOrder() *validateOrder {
+	now := time.Now()
+	return &validateOrder{
+		Name:     "ray",
+		Status:   "new",
+		Password: "secret",
+		Confirm:  "secret",
+		Start:    now,
+		End:      now.Add(time.Hour),
+		Tags:     []string{"a", "b"},
+		Items:    []validateItem{{SKU: "a1", Qty: 1}},
+		Meta:     map[string]string{"color": "red"},
+	}
+}
+
+func TestDefaultValidator(t *testing.T) {
+	testCases := []struct {
+		name    string
+		modify  func(o *validateOrder)
+		wantErr []string
+	}{
+		{
+			name:   "valid",
+			modify: func(o *validateOrder) {},
+		},
+		{
+			name: "required and size",
+			modify: func(o *validateOrder) {
+				o.Name = ""
+				o.Tags = []string{"a", "b", "c", "d"}
+			},
+			wantErr: []string{"Name:required", "Tags:max=3"},
+		},
+		{
+			name: "email and oneof",
+			modify: func(o *validateOrder) {
+				o.Email = "ray"
+				o.Status = "closed"
+			},
+			wantErr: []string{"Email:email", "Status:oneof=new paid"},
+		},
+		{
+			name: "cross field",
+			modify: func(o *validateOrder) {
+				o.Confirm = "other"
+				o.End = o.Start.Add(-time.Hour)
+			},
+			wantErr: []string{"Confirm:eqfield=Password", "End:gtfield=Start"},
+		},
+		{
+			name: "dive",
+			modify: func(o *validateOrder) {
+				o.Tags = []string{"", "toolong"}
+				o.Items = append(o.Items, validateItem{SKU: "b-2", Qty: 0})
+				o.Meta["size"] = "42"
+			},
+			wantErr: []string{"Tags[0]:required", "Tags[1]:max=4", "Items[1].SKU:alphanum", "Items[1].Qty:gte=1", "Meta[size]:alpha"},
+		},
+		{
+			name: "nested pointer",
+			modify: func(o *validateOrder) {
+				note := "too long"
+				o.Note = &note
+				o.Shipping = &struct {
+					City string `json:"city" binding:"required"`
+				}{}
+			},
+			wantErr: []string{"Note:max=4", "Shipping.City:required"},
+		},
+	}
+
+	v := &defaultValidator{}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			o := validOrder()
+			tt.modify(o)
+			err := v.ValidateStruct(o)
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var errs ValidationErrors
+			require.True(t, errors.As(err, &errs))
+			got := make([]string, 0, len(errs))
+			for _, e := range errs {
+				rule := e.Tag
+				if e.Param != "" {
+					rule += "=" + e.Param
+				}
+				got = append(got, e.Field+":"+rule)
+			}
+			assert.Equal(t, tt.wantErr, got)
+		})
+	}
+}
+
+type validateBase struct {
+	ID int `form:"id" binding:"gte=1"`
+}
+
+type validateProfile struct {
+	validateBase
+	Address struct {
+		*validateBase
+	} `form:"address"`
+}
+
+func TestDefaultValidator_Embedded(t *testing.T) {
+	// fields of embedded structs are reported by the same path as BindingError
+	var obj validateProfile
+	obj.Address.validateBase = &validateBase{}
+	err := (&defaultValidator{}).ValidateStruct(&obj)
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 2)
+	assert.Equal(t, "ID", errs[0].Field)
+	assert.Equal(t, "Address.ID", errs[1].Field)
+
+	var bindErr *BindingError
+	err = mapForm(&obj, map[string][]string{"id": {"x"}})
+	require.True(t, errors.As(err, &bindErr))
+	assert.Equal(t, errs[0].Field, bindErr.Fields[0].Path)
+}
+
+func TestRegisterRule(t *testing.T) {
+	RegisterRule("even", func(fl FieldLevel) bool {
+		return fl.Field.Int()%2 == 0
+	})
+
+	var obj struct {
+		N int `form:"n" binding:"even"`
+	}
+	req, err := http.NewRequest(http.MethodGet, "/?n=3", nil)
+	require.NoError(t, err)
+	err = QUERY.Bind(req, &obj)
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, "even", errs[0].Tag)
+
+	var undefined struct {
+		N int `binding:"odd"`
+	}
+	err = Validator.ValidateStruct(&undefined)
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &errs))
+}
+
+func TestJSONBindingValidate(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"items":[{"sku":"a1","qty":1},{"sku":"","qty":1}]}`))
+	require.NoError(t, err)
+
+	var o struct {
+		Items []validateItem `json:"items" binding:"required,dive"`
+	}
+	err = JSON.Bind(req, &o)
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Items[1].SKU", errs[0].Field)
+}
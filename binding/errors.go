@@ -0,0 +1,143 @@
+package binding
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source is the part of the request a value is bound from
+type Source string
+
+const (
+	SourceQuery  Source = "query"
+	SourceForm   Source = "form"
+	SourceURI    Source = "uri"
+	SourceHeader Source = "header"
+	SourceBody   Source = "body"
+)
+
+// ProblemContentType is the content type of a RFC 7807 problem details response
+const ProblemContentType = "application/problem+json"
+
+// FieldBindError describes a field which could not be bound
+type FieldBindError struct {
+	Path   string `json:"path"`            // path of the field, e.g. User.Age
+	Name   string `json:"name"`            // name of the value in the request, e.g. age
+	Source Source `json:"source"`          // where the value comes from
+	Value  string `json:"value,omitempty"` // offending value
+	Reason string `json:"reason"`          // why the value was rejected
+	Err    error  `json:"-"`               // underlying error
+}
+
+func (e *FieldBindError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s: %s", e.Source, e.Reason)
+	}
+	return fmt.Sprintf("%s '%s' of field '%s': %s", e.Source, e.Name, e.Path, e.Reason)
+}
+
+func (e *FieldBindError) Unwrap() error {
+	return e.Err
+}
+
+// BindingError is returned by the bindings when a request can not be bound,
+// it lists every failed field rather than only the first one.
+type BindingError struct {
+	Fields []*FieldBindError
+}
+
+func (e *BindingError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Problem is a RFC 7807 problem details response
+type Problem struct {
+	Type   string            `json:"type"`
+	Title  string            `json:"title"`
+	Status int               `json:"status"`
+	Detail string            `json:"detail,omitempty"`
+	Errors []*FieldBindError `json:"errors,omitempty"`
+}
+
+// Problem converts the error to a problem details response which can be
+// written as JSON with the ProblemContentType.
+func (e *BindingError) Problem() *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: fmt.Sprintf("%d field(s) of the request could not be bound", len(e.Fields)),
+		Errors: e.Fields,
+	}
+}
+
+// valueError attaches the offending value to err
+func valueError(val string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*FieldBindError); ok {
+		return err
+	}
+	return &FieldBindError{Value: val, Err: err}
+}
+
+// errorReason describes why a value can not be set to a field of type t
+func errorReason(err error, t reflect.Type) string {
+	var numErr *strconv.NumError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &numErr):
+		if errors.Is(numErr.Err, strconv.ErrRange) {
+			return fmt.Sprintf("value out of range for %s", t)
+		}
+		return fmt.Sprintf("invalid value for %s", t)
+	case errors.As(err, &timeErr):
+		return fmt.Sprintf("invalid time, expected layout %s", timeErr.Layout)
+	case errors.Is(err, errUnknownType):
+		return fmt.Sprintf("unsupported type %s", t)
+	}
+	return err.Error()
+}
+
+// bodyError converts the errors of the body decoders to a *BindingError
+func bodyError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	var jsonSyntaxErr *json.SyntaxError
+	var xmlSyntaxErr *xml.SyntaxError
+	var fe *FieldBindError
+	switch {
+	case errors.As(err, &typeErr):
+		fe = &FieldBindError{
+			Path:   typeErr.Field,
+			Name:   typeErr.Field,
+			Value:  typeErr.Value,
+			Reason: fmt.Sprintf("invalid value for %s", typeErr.Type),
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		name, _ := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		fe = &FieldBindError{Path: name, Name: name, Reason: "unknown field"}
+	case errors.Is(err, errTrailingData):
+		fe = &FieldBindError{Reason: "unexpected data after the JSON value"}
+	case errors.As(err, &jsonSyntaxErr):
+		fe = &FieldBindError{Reason: fmt.Sprintf("malformed JSON at offset %d", jsonSyntaxErr.Offset)}
+	case errors.As(err, &xmlSyntaxErr):
+		fe = &FieldBindError{Reason: fmt.Sprintf("malformed XML at line %d", xmlSyntaxErr.Line)}
+	default:
+		return err
+	}
+	fe.Source = SourceBody
+	fe.Err = err
+	return &BindingError{Fields: []*FieldBindError{fe}}
+}
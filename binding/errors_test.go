@@ -0,0 +1,77 @@
+package binding
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBindingError(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/?age=abc&level=300&ids=1&ids=x&name=ray", nil)
+	require.NoError(t, err)
+
+	var obj struct {
+		Name  string `form:"name"`
+		Age   int    `form:"age"`
+		Level int8   `form:"level"`
+		Inner struct {
+			IDs []int `form:"ids"`
+		}
+	}
+	err = QUERY.Bind(req, &obj)
+
+	var bindErr *BindingError
+	require.True(t, errors.As(err, &bindErr))
+	assert.Equal(t, []*FieldBindError{
+		{Path: "Age", Name: "age", Source: SourceQuery, Value: "abc", Reason: "invalid value for int"},
+		{Path: "Level", Name: "level", Source: SourceQuery, Value: "300", Reason: "value out of range for int8"},
+		{Path: "Inner.IDs", Name: "ids", Source: SourceQuery, Value: "x", Reason: "invalid value for []int"},
+	}, clearErr(bindErr.Fields))
+	assert.Equal(t, "ray", obj.Name)
+
+	data, err := json.Marshal(bindErr.Problem())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "3 field(s) of the request could not be bound",
+		"errors": [
+			{"path": "Age", "name": "age", "source": "query", "value": "abc", "reason": "invalid value for int"},
+			{"path": "Level", "name": "level", "source": "query", "value": "300", "reason": "value out of range for int8"},
+			{"path": "Inner.IDs", "name": "ids", "source": "query", "value": "x", "reason": "invalid value for []int"}
+		]
+	}`, string(data))
+}
+
+func TestBindingError_Body(t *testing.T) {
+	var obj struct {
+		Age int `json:"age"`
+	}
+
+	err := JSON.BindBody([]byte(`{"age":"abc"}`), &obj)
+	var bindErr *BindingError
+	require.True(t, errors.As(err, &bindErr))
+	assert.Equal(t, []*FieldBindError{
+		{Path: "age", Name: "age", Source: SourceBody, Value: "string", Reason: "invalid value for int"},
+	}, clearErr(bindErr.Fields))
+
+	err = XML.Bind(&http.Request{Body: http.NoBody}, &obj)
+	assert.False(t, errors.As(err, &bindErr))
+
+	err = JSON.BindBody([]byte(`{"age":`+strings.Repeat(" ", 3)+`}`), &obj)
+	require.True(t, errors.As(err, &bindErr))
+	assert.Equal(t, SourceBody, bindErr.Fields[0].Source)
+	assert.Equal(t, "", bindErr.Fields[0].Path)
+}
+
+func clearErr(fields []*FieldBindError) []*FieldBindError {
+	for _, f := range fields {
+		f.Err = nil
+	}
+	return fields
+}
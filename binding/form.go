@@ -0,0 +1,62 @@
+package binding
+
+import (
+	"errors"
+	"net/http"
+)
+
+// MaxMultipartMemory is the maximum number of bytes of a multipart form kept in
+// memory while parsing, the remainder is stored on disk in temporary files.
+var MaxMultipartMemory int64 = 32 << 20 // 32 MB
+
+type formBinding struct{}
+type formPostBinding struct{}
+type formMultipartBinding struct{}
+
+func (formBinding) Name() string {
+	return "form"
+}
+
+// Bind (form) binds url query, urlencoded body and multipart values
+func (formBinding) Bind(req *http.Request, obj any) error {
+	if err := req.ParseForm(); err != nil {
+		return err
+	}
+	if err := req.ParseMultipartForm(MaxMultipartMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return err
+	}
+	if err := mapForm(obj, req.Form); err != nil {
+		return err
+	}
+	return validate(obj)
+}
+
+func (formPostBinding) Name() string {
+	return "form-urlencoded"
+}
+
+// Bind (form-urlencoded) binds values of the request body only
+func (formPostBinding) Bind(req *http.Request, obj any) error {
+	if err := req.ParseForm(); err != nil {
+		return err
+	}
+	if err := mapForm(obj, req.PostForm); err != nil {
+		return err
+	}
+	return validate(obj)
+}
+
+func (formMultipartBinding) Name() string {
+	return "multipart/form-data"
+}
+
+// Bind (multipart/form-data) binds values and uploaded files of a multipart body
+func (formMultipartBinding) Bind(req *http.Request, obj any) error {
+	if err := req.ParseMultipartForm(MaxMultipartMemory); err != nil {
+		return err
+	}
+	if err := mappingByPtr(obj, (*multipartRequest)(req), "form", SourceForm); err != nil {
+		return err
+	}
+	return validate(obj)
+}
@@ -1,13 +1,16 @@
 package binding
 
 import (
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/killlowkey/web/internal/bytesconv"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,23 +22,43 @@ var (
 
 	// ErrConvertToMapString can not convert to map[string]string
 	ErrConvertToMapString = errors.New("can not convert to map of strings")
+
+	// ErrTooManyFormKeys the nested keys exceed MaxNestedFormKeys or MaxFormEntries
+	ErrTooManyFormKeys = errors.New("too many nested form keys")
 )
 
+// MaxFormIndex is the largest index accepted in nested keys like items[0][sku],
+// it prevents hostile inputs from allocating huge slices.
+var MaxFormIndex = 1000
+
+// MaxNestedFormKeys is the largest number of nested keys like items[0][sku]
+// accepted in one bind.
+var MaxNestedFormKeys = 10000
+
+// MaxFormEntries is the largest number of slice and map elements created from
+// nested keys in one bind, e.g. items[1000][tags][1000] creates 1001 elements
+// for every item.
+var MaxFormEntries = 10000
+
 func mapURI(ptr any, m map[string][]string) error {
-	return mapFormByTag(ptr, m, "uri")
+	return mapFormByTag(ptr, m, "uri", SourceURI)
+}
+
+func mapQuery(ptr any, query map[string][]string) error {
+	return mapFormByTag(ptr, query, "form", SourceQuery)
 }
 
 func mapForm(ptr any, form map[string][]string) error {
-	return mapFormByTag(ptr, form, "form")
+	return mapFormByTag(ptr, form, "form", SourceForm)
 }
 
 func MapFormWithTag(ptr any, form map[string][]string, tag string) error {
-	return mapFormByTag(ptr, form, tag)
+	return mapFormByTag(ptr, form, tag, SourceForm)
 }
 
 var emptyField = reflect.StructField{}
 
-func mapFormByTag(ptr any, form map[string][]string, tag string) error {
+func mapFormByTag(ptr any, form map[string][]string, tag string, source Source) error {
 	// Check if ptr is a map
 	ptrVal := reflect.ValueOf(ptr)
 	var pointed any
@@ -51,7 +74,7 @@ func mapFormByTag(ptr any, form map[string][]string, tag string) error {
 		return setFormMap(ptr, form)
 	}
 
-	return mappingByPtr(ptr, formSource(form), tag)
+	return mappingByPtr(ptr, formSource(form), tag, source)
 }
 
 // setter tries to set value on a walking by fields of a struct
@@ -68,14 +91,195 @@ func (form formSource) TrySet(value reflect.Value, field reflect.StructField, ta
 	return setByForm(value, field, form, tagValue, opt)
 }
 
-func mappingByPtr(ptr any, setter setter, tag string) error {
-	_, err := mapping(reflect.ValueOf(ptr), emptyField, setter, tag)
-	return err
+// nestedSource is implemented by the setters whose values may use nested keys,
+// both bracket notation user[name] and dot notation user.name are supported.
+type nestedSource interface {
+	setter
+	// formValues returns the values which may use nested keys
+	formValues() map[string][]string
 }
 
-func mapping(value reflect.Value, field reflect.StructField, setter setter, tag string) (bool, error) {
-	if field.Tag.Get(tag) == "-" { // just ignoring this field
-		return false, nil
+var _ nestedSource = formSource(nil)
+
+func (form formSource) formValues() map[string][]string {
+	return form
+}
+
+// formIndex groups the nested keys by their first segment. The index is built
+// once per bind and each level is grouped at most once, so looking up the keys
+// nested under a field never walks every key of the form.
+type formIndex struct {
+	values   formSource  // values by the single segment key relative to this level
+	entries  []formEntry // keys with more than one segment, grouped on first use
+	children map[string]*formIndex
+	keys     []string // distinct first segments
+	seen     map[string]struct{}
+	sorted   bool
+}
+
+type formEntry struct {
+	segments []string
+	values   []string
+}
+
+var _ setter = (*formIndex)(nil)
+
+// newFormIndex indexes the keys of form which use nested notation, nil is
+// returned when there is none.
+func newFormIndex(form map[string][]string) (*formIndex, error) {
+	var keys []string
+	for k := range form {
+		if strings.ContainsAny(k, ".[") {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if len(keys) > MaxNestedFormKeys {
+		return nil, ErrTooManyFormKeys
+	}
+	// keys are sorted so that items[0]=a&items.0=b always merge in the same order
+	sort.Strings(keys)
+
+	entries := make([]formEntry, 0, len(keys))
+	for _, k := range keys {
+		entries = append(entries, formEntry{segments: splitKey(k), values: form[k]})
+	}
+	return &formIndex{entries: entries}, nil
+}
+
+// TrySet tries to set a value by the values relative to this level
+func (idx *formIndex) TrySet(value reflect.Value, field reflect.StructField, key string, opt setOptions) (bool, error) {
+	return idx.values.TrySet(value, field, key, opt)
+}
+
+// child returns the level of the keys nested under name. name is looked up as
+// one segment first, e.g. the map key example.com of meta[example.com], then a
+// dotted tag like a.b descends one level per segment.
+func (idx *formIndex) child(name string) (*formIndex, bool) {
+	idx.group()
+	if next, ok := idx.children[name]; ok {
+		return next, true
+	}
+	if strings.IndexByte(name, '.') < 0 {
+		return nil, false
+	}
+	for name != "" {
+		var segment string
+		segment, name = head(name, ".")
+		idx.group()
+		next, ok := idx.children[segment]
+		if !ok {
+			return nil, false
+		}
+		idx = next
+	}
+	return idx, true
+}
+
+// group splits the entries by their first segment
+func (idx *formIndex) group() {
+	if idx.children != nil {
+		return
+	}
+	idx.children = make(map[string]*formIndex)
+	for _, e := range idx.entries {
+		h, rest := e.segments[0], e.segments[1:]
+		if h == "" || len(rest) == 0 || rest[0] == "" {
+			continue
+		}
+		c, ok := idx.children[h]
+		if !ok {
+			c = &formIndex{values: formSource{}, seen: map[string]struct{}{}}
+			idx.children[h] = c
+		}
+		if _, ok := c.seen[rest[0]]; !ok {
+			c.seen[rest[0]] = struct{}{}
+			c.keys = append(c.keys, rest[0])
+		}
+		if len(rest) == 1 {
+			c.values[rest[0]] = append(c.values[rest[0]], e.values...)
+			continue
+		}
+		c.entries = append(c.entries, formEntry{segments: rest, values: e.values})
+	}
+	idx.entries = nil
+}
+
+// heads returns the distinct first segments of the keys, sorted
+func (idx *formIndex) heads() []string {
+	if !idx.sorted {
+		sort.Strings(idx.keys)
+		idx.sorted = true
+	}
+	return idx.keys
+}
+
+// splitKey splits a key into segments, the content of brackets is kept as one
+// segment even if it contains a dot: items[0][sku] => items 0 sku,
+// user.name => user name, meta[example.com] => meta example.com.
+func splitKey(key string) []string {
+	var segments []string
+	for len(key) > 0 {
+		if key[0] == '[' {
+			end := strings.IndexByte(key, ']')
+			if end < 0 { // unclosed bracket, the rest is one segment
+				return append(segments, key[1:])
+			}
+			segments = append(segments, key[1:end])
+			key = strings.TrimPrefix(key[end+1:], ".")
+			continue
+		}
+		end := strings.IndexAny(key, ".[")
+		if end < 0 {
+			return append(segments, key)
+		}
+		segments = append(segments, key[:end])
+		if key[end] == '.' {
+			end++
+		}
+		key = key[end:]
+	}
+	return segments
+}
+
+// mappingByPtr binds the setter to ptr, a *BindingError listing every failed
+// field is returned instead of stopping at the first failure.
+func mappingByPtr(ptr any, setter setter, tag string, source Source) error {
+	m := &mapper{tag: tag, source: source}
+	if ns, ok := setter.(nestedSource); ok {
+		index, err := newFormIndex(ns.formValues())
+		if err != nil {
+			return &BindingError{Fields: []*FieldBindError{{
+				Source: source,
+				Reason: fmt.Sprintf("more than %d nested keys", MaxNestedFormKeys),
+				Err:    err,
+			}}}
+		}
+		m.index = index
+	}
+	m.mapping(reflect.ValueOf(ptr), emptyField, setter, "")
+	if len(m.errs) > 0 {
+		return &BindingError{Fields: m.errs}
+	}
+	return nil
+}
+
+// mapper walks by fields of a struct and collects the fields which failed
+type mapper struct {
+	tag    string
+	source Source
+	errs   []*FieldBindError
+	// index of the nested keys, nil when the setter has none
+	index *formIndex
+	// entries counts the slice and map elements created from nested keys
+	entries int
+}
+
+func (m *mapper) mapping(value reflect.Value, field reflect.StructField, setter setter, path string) bool {
+	if field.Tag.Get(m.tag) == "-" { // just ignoring this field
+		return false
 	}
 
 	vKind := value.Kind()
@@ -87,23 +291,30 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 			isNew = true
 			vPtr = reflect.New(value.Type().Elem())
 		}
-		isSet, err := mapping(vPtr.Elem(), field, setter, tag)
-		if err != nil {
-			return false, err
-		}
+		isSet := m.mapping(vPtr.Elem(), field, setter, path)
 		if isNew && isSet {
 			value.Set(vPtr)
 		}
-		return isSet, nil
+		return isSet
 	}
 
 	if vKind != reflect.Struct || !field.Anonymous {
-		ok, err := tryToSetValue(value, field, setter, tag)
+		ok, err := tryToSetValue(value, field, setter, m.tag)
 		if err != nil {
-			return false, err
+			m.fail(path, value.Type(), err)
+			return false
 		}
 		if ok {
-			return true, nil
+			return true
+		}
+	}
+
+	// user[name]=x or user.name=x binds the nested user struct,
+	// otherwise the fields of nested structs are bound by their own keys
+	if m.index != nil && field.Name != "" && !field.Anonymous {
+		key, _ := parseTag(field, m.tag)
+		if sub, found := m.index.child(key); found {
+			return m.mappingNested(value, field, sub, path, key)
 		}
 	}
 
@@ -116,15 +327,180 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 			if sf.PkgPath != "" && !sf.Anonymous { // unexported
 				continue
 			}
-			ok, err := mapping(value.Field(i), sf, setter, tag)
-			if err != nil {
-				return false, err
+			ok := m.mapping(value.Field(i), sf, setter, fieldPath(path, sf))
+			isSet = isSet || ok
+		}
+		return isSet
+	}
+	return false
+}
+
+// mappingNested binds the values of ns which are nested under the field, e.g.
+// the keys of user[name], items[0][sku] and meta[color] relative to the field.
+func (m *mapper) mappingNested(value reflect.Value, field reflect.StructField, ns *formIndex, path, name string) bool {
+	switch value.Kind() {
+	case reflect.Struct:
+		tValue := value.Type()
+
+		var isSet bool
+		for i := 0; i < value.NumField(); i++ {
+			sf := tValue.Field(i)
+			if sf.PkgPath != "" && !sf.Anonymous { // unexported
+				continue
+			}
+			if sf.Tag.Get(m.tag) == "-" {
+				continue
+			}
+			var ok bool
+			if sf.Anonymous {
+				ok = m.mappingNested(reflect.Indirect(value.Field(i)), sf, ns, path, name)
+			} else {
+				key, opt := parseTag(sf, m.tag)
+				ok = m.mappingKey(value.Field(i), sf, ns, key, opt, fieldPath(path, sf), name+"["+key+"]")
 			}
 			isSet = isSet || ok
 		}
-		return isSet, nil
+		return isSet
+	case reflect.Slice, reflect.Array:
+		keys := ns.heads()
+		indexes := make([]int, 0, len(keys))
+		maxIndex := -1
+		for _, key := range keys {
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx > MaxFormIndex || (value.Kind() == reflect.Array && idx >= value.Len()) {
+				m.fail(path, value.Type(), &FieldBindError{
+					Name:   name + "[" + key + "]",
+					Reason: fmt.Sprintf("invalid index %q for %s", key, value.Type()),
+					Err:    fmt.Errorf("invalid index %q", key),
+				})
+				return false
+			}
+			indexes = append(indexes, idx)
+			if idx > maxIndex {
+				maxIndex = idx
+			}
+		}
+
+		if !m.grow(path, value.Type(), name, maxIndex+1) {
+			return false
+		}
+		slice := value
+		if value.Kind() == reflect.Slice {
+			slice = reflect.MakeSlice(value.Type(), maxIndex+1, maxIndex+1)
+		}
+		var isSet bool
+		for i, idx := range indexes {
+			el := "[" + strconv.Itoa(idx) + "]"
+			ok := m.mappingKey(slice.Index(idx), field, ns, keys[i], setOptions{}, path+el, name+el)
+			isSet = isSet || ok
+		}
+		if isSet && value.Kind() == reflect.Slice {
+			value.Set(slice)
+		}
+		return isSet
+	case reflect.Map:
+		tValue := value.Type()
+		mv := value
+		if value.IsNil() {
+			mv = reflect.MakeMap(tValue)
+		}
+
+		keys := ns.heads()
+		if !m.grow(path, tValue, name, len(keys)) {
+			return false
+		}
+		var isSet bool
+		for _, key := range keys {
+			el := "[" + key + "]"
+			k := reflect.New(tValue.Key()).Elem()
+			if err := setWithProperType(key, k, field); err != nil {
+				m.fail(path+el, k.Type(), &FieldBindError{Name: name + el, Value: key, Err: err})
+				continue
+			}
+			v := reflect.New(tValue.Elem()).Elem()
+			if m.mappingKey(v, field, ns, key, setOptions{}, path+el, name+el) {
+				mv.SetMapIndex(k, v)
+				isSet = true
+			}
+		}
+		if isSet && value.IsNil() {
+			value.Set(mv)
+		}
+		return isSet
 	}
-	return false, nil
+	return false
+}
+
+// mappingKey binds the value of key in ns, or the values nested under key
+func (m *mapper) mappingKey(value reflect.Value, field reflect.StructField, ns *formIndex, key string, opt setOptions, path, name string) bool {
+	if value.Kind() == reflect.Ptr {
+		var isNew bool
+		vPtr := value
+		if value.IsNil() {
+			isNew = true
+			vPtr = reflect.New(value.Type().Elem())
+		}
+		isSet := m.mappingKey(vPtr.Elem(), field, ns, key, opt, path, name)
+		if isNew && isSet {
+			value.Set(vPtr)
+		}
+		return isSet
+	}
+
+	isSet, err := trySet(ns, value, field, key, opt)
+	if err != nil {
+		err.(*FieldBindError).Name = name
+		m.fail(path, value.Type(), err)
+		return false
+	}
+	if isSet {
+		return true
+	}
+
+	if sub, found := ns.child(key); found {
+		return m.mappingNested(value, field, sub, path, name)
+	}
+	return false
+}
+
+// grow counts n elements created for the field at path, the field fails once
+// more than MaxFormEntries elements are created in one bind
+func (m *mapper) grow(path string, t reflect.Type, name string, n int) bool {
+	m.entries += n
+	if m.entries <= MaxFormEntries {
+		return true
+	}
+	m.fail(path, t, &FieldBindError{
+		Name:   name,
+		Reason: fmt.Sprintf("more than %d nested elements", MaxFormEntries),
+		Err:    ErrTooManyFormKeys,
+	})
+	return false
+}
+
+// fail records the error of the field at path
+func (m *mapper) fail(path string, t reflect.Type, err error) {
+	fe, ok := err.(*FieldBindError)
+	if !ok {
+		fe = &FieldBindError{Err: err}
+	}
+	fe.Path = path
+	fe.Source = m.source
+	if fe.Reason == "" {
+		fe.Reason = errorReason(fe.Err, t)
+	}
+	m.errs = append(m.errs, fe)
+}
+
+// fieldPath appends the field to path, fields of embedded structs are promoted
+func fieldPath(path string, sf reflect.StructField) string {
+	switch {
+	case sf.Anonymous:
+		return path
+	case path == "":
+		return sf.Name
+	}
+	return path + "." + sf.Name
 }
 
 type setOptions struct {
@@ -133,18 +509,23 @@ type setOptions struct {
 }
 
 func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter, tag string) (bool, error) {
-	var tagValue string
+	tagValue, setOpt := parseTag(field, tag)
+	if tagValue == "" { // when field is "emptyField" variable
+		return false, nil
+	}
+	return trySet(setter, value, field, tagValue, setOpt)
+}
+
+// parseTag returns the key and the options of the field
+func parseTag(field reflect.StructField, tag string) (string, setOptions) {
 	var setOpt setOptions
 
-	tagValue = field.Tag.Get(tag)
+	tagValue := field.Tag.Get(tag)
 	tagValue, opts := head(tagValue, ",")
 
 	if tagValue == "" { // default value is FieldName
 		tagValue = field.Name
 	}
-	if tagValue == "" { // when field is "emptyField" variable
-		return false, nil
-	}
 
 	var opt string
 	for len(opts) > 0 {
@@ -155,8 +536,23 @@ func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter
 			setOpt.defaultValue = v
 		}
 	}
+	return tagValue, setOpt
+}
 
-	return setter.TrySet(value, field, tagValue, setOpt)
+// trySet sets the value by the setter, errors are returned as *FieldBindError
+func trySet(setter setter, value reflect.Value, field reflect.StructField, tagValue string, setOpt setOptions) (bool, error) {
+	isSet, err := setter.TrySet(value, field, tagValue, setOpt)
+	if err != nil {
+		fe, ok := err.(*FieldBindError)
+		if !ok {
+			fe = &FieldBindError{Err: err}
+		}
+		if fe.Name == "" {
+			fe.Name = tagValue
+		}
+		return isSet, fe
+	}
+	return isSet, nil
 }
 
 func setByForm(value reflect.Value, field reflect.StructField, form map[string][]string, tagValue string, opt setOptions) (isSet bool, err error) {
@@ -165,6 +561,17 @@ func setByForm(value reflect.Value, field reflect.StructField, form map[string][
 		return false, nil
 	}
 
+	// slice types like net.IP unmarshal a single value by themselves
+	if k := value.Kind(); k == reflect.Slice || k == reflect.Array {
+		val := opt.defaultValue
+		if len(vs) > 0 {
+			val = vs[0]
+		}
+		if isSet, err := trySetCustom(val, value); isSet {
+			return true, valueError(val, err)
+		}
+	}
+
 	switch value.Kind() {
 	case reflect.Slice:
 		if !ok {
@@ -176,7 +583,11 @@ func setByForm(value reflect.Value, field reflect.StructField, form map[string][
 			vs = []string{opt.defaultValue}
 		}
 		if len(vs) != value.Len() {
-			return false, fmt.Errorf("%q is not valid value for %s", vs, value.Type().String())
+			return false, &FieldBindError{
+				Value:  strings.Join(vs, ","),
+				Reason: fmt.Sprintf("expected %d values for %s", value.Len(), value.Type().String()),
+				Err:    fmt.Errorf("%q is not valid value for %s", vs, value.Type().String()),
+			}
 		}
 		return true, setArray(vs, value, field)
 	default:
@@ -188,12 +599,65 @@ func setByForm(value reflect.Value, field reflect.StructField, form map[string][
 		if len(vs) > 0 {
 			val = vs[0]
 		}
-		return true, setWithProperType(val, value, field)
+		return true, valueError(val, setWithProperType(val, value, field))
+	}
+}
+
+// BindUnmarshaler is implemented by types which can unmarshal themselves from
+// a query, form, uri or header value.
+type BindUnmarshaler interface {
+	// UnmarshalParam decodes and assigns a value from a request value
+	UnmarshalParam(param string) error
+}
+
+// converters holds the registered converters, map[reflect.Type]func(string, reflect.Value) error
+var converters sync.Map
+
+// RegisterConverter registers a function converting request values to T, it takes
+// precedence over BindUnmarshaler, encoding.TextUnmarshaler and the built-in kinds.
+func RegisterConverter[T any](fn func(val string) (T, error)) {
+	converters.Store(reflect.TypeOf((*T)(nil)).Elem(), func(val string, value reflect.Value) error {
+		v, err := fn(val)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(&v).Elem())
+		return nil
+	})
+}
+
+// trySetCustom sets the value by a registered converter or the unmarshal
+// method of the type, time.Time is left to the time_format tag.
+func trySetCustom(val string, value reflect.Value) (isSet bool, err error) {
+	if conv, ok := converters.Load(value.Type()); ok {
+		return true, conv.(func(string, reflect.Value) error)(val, value)
+	}
+	if !value.CanAddr() {
+		return false, nil
+	}
+
+	switch v := value.Addr().Interface().(type) {
+	case BindUnmarshaler:
+		return true, v.UnmarshalParam(val)
+	case *time.Time:
+		return false, nil
+	case encoding.TextUnmarshaler:
+		return true, v.UnmarshalText(bytesconv.StringToBytes(val))
 	}
+	return false, nil
 }
 
 func setWithProperType(val string, value reflect.Value, field reflect.StructField) error {
+	if ok, err := trySetCustom(val, value); ok {
+		return err
+	}
+
 	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return setWithProperType(val, value.Elem(), field)
 	case reflect.Int:
 		return setIntField(val, 0, value)
 	case reflect.Int8:
@@ -338,7 +802,7 @@ func setArray(vals []string, value reflect.Value, field reflect.StructField) err
 	for i, s := range vals {
 		err := setWithProperType(s, value.Index(i), field)
 		if err != nil {
-			return err
+			return valueError(s, err)
 		}
 	}
 	return nil
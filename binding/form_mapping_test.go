@@ -0,0 +1,185 @@
+package binding
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type nestedItem struct {
+	SKU  string   `form:"sku"`
+	Qty  int      `form:"qty,default=1"`
+	Tags []string `form:"tags"`
+	KV   string   `form:"k.v"`
+}
+
+type nestedOrder struct {
+	User struct {
+		Name string `form:"name"`
+		Age  int    `form:"age"`
+	} `form:"user"`
+	Items   []nestedItem      `form:"items"`
+	Ptrs    []*nestedItem     `form:"ptrs"`
+	Meta    map[string]string `form:"meta"`
+	Scores  map[string]int    `form:"scores"`
+	IDs     []int             `form:"ids"`
+	Address *struct {
+		City string `form:"city"`
+	} `form:"address"`
+}
+
+func TestMapForm_Nested(t *testing.T) {
+	form, err := url.ParseQuery("user[name]=ray&user.age=18" +
+		"&items[1][sku]=b&items[0][sku]=a&items[0][qty]=2&items.1.tags=x&items[1][tags]=y" +
+		"&ptrs[0][sku]=p&meta[color]=red&meta.size=L&scores[math]=90&ids[0]=1&ids[1]=2&address[city]=sz" +
+		"&meta[a.b]=x&items[0][k.v]=y")
+	require.NoError(t, err)
+
+	var obj nestedOrder
+	require.NoError(t, mapForm(&obj, form))
+	assert.Equal(t, "ray", obj.User.Name)
+	assert.Equal(t, 18, obj.User.Age)
+	assert.Equal(t, []nestedItem{
+		{SKU: "a", Qty: 2, KV: "y"},
+		{SKU: "b", Qty: 1, Tags: []string{"x", "y"}},
+	}, obj.Items)
+	require.Len(t, obj.Ptrs, 1)
+	assert.Equal(t, &nestedItem{SKU: "p", Qty: 1}, obj.Ptrs[0])
+	assert.Equal(t, map[string]string{"color": "red", "size": "L", "a.b": "x"}, obj.Meta)
+	assert.Equal(t, map[string]int{"math": 90}, obj.Scores)
+	assert.Equal(t, []int{1, 2}, obj.IDs)
+	require.NotNil(t, obj.Address)
+	assert.Equal(t, "sz", obj.Address.City)
+}
+
+func TestMapForm_NestedFlatFallback(t *testing.T) {
+	var obj nestedOrder
+	require.NoError(t, mapForm(&obj, url.Values{"name": {"ray"}}))
+	assert.Equal(t, "ray", obj.User.Name)
+	assert.Nil(t, obj.Address)
+}
+
+func TestMapForm_NestedErrors(t *testing.T) {
+	var obj nestedOrder
+	err := mapForm(&obj, url.Values{
+		"items[0][qty]": {"abc"},
+		"scores[math]":  {"x"},
+	})
+	var bindErr *BindingError
+	require.True(t, errors.As(err, &bindErr))
+	require.Len(t, bindErr.Fields, 2)
+	assert.Equal(t, "Items[0].Qty", bindErr.Fields[0].Path)
+	assert.Equal(t, "items[0][qty]", bindErr.Fields[0].Name)
+	assert.Equal(t, "abc", bindErr.Fields[0].Value)
+	assert.Equal(t, "Scores[math]", bindErr.Fields[1].Path)
+	assert.Equal(t, "scores[math]", bindErr.Fields[1].Name)
+
+	obj = nestedOrder{}
+	err = mapForm(&obj, url.Values{"items[" + strconv.Itoa(MaxFormIndex+1) + "][sku]": {"a"}})
+	require.True(t, errors.As(err, &bindErr))
+	assert.Equal(t, "Items", bindErr.Fields[0].Path)
+	assert.Nil(t, obj.Items)
+}
+
+func TestMapForm_NestedLimits(t *testing.T) {
+	// every level is grouped once, unknown sub-keys and junk keys are not walked per field
+	form := url.Values{}
+	for i := 0; i <= MaxFormIndex; i++ {
+		form.Set("items["+strconv.Itoa(i)+"][sku]", strconv.Itoa(i))
+		for j := 0; j < 5; j++ {
+			form.Set("items["+strconv.Itoa(i)+"][x"+strconv.Itoa(j)+"]", "junk")
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		form.Set("junk"+strconv.Itoa(i), "x")
+	}
+	var obj nestedOrder
+	require.NoError(t, mapForm(&obj, form))
+	require.Len(t, obj.Items, MaxFormIndex+1)
+	assert.Equal(t, strconv.Itoa(MaxFormIndex), obj.Items[MaxFormIndex].SKU)
+
+	var bindErr *BindingError
+	form = url.Values{}
+	for i := 0; i <= MaxNestedFormKeys; i++ {
+		form.Set("meta["+strconv.Itoa(i)+"]", "x")
+	}
+	obj = nestedOrder{}
+	err := mapForm(&obj, form)
+	require.True(t, errors.As(err, &bindErr))
+	assert.ErrorIs(t, bindErr.Fields[0], ErrTooManyFormKeys)
+	assert.Nil(t, obj.Meta)
+
+	// items[i][tags][1000] would create a slice of 1001 tags for every item
+	form = url.Values{}
+	for i := 0; i < 20; i++ {
+		form.Set("items["+strconv.Itoa(i)+"][tags]["+strconv.Itoa(MaxFormIndex)+"]", "x")
+	}
+	obj = nestedOrder{}
+	err = mapForm(&obj, form)
+	require.True(t, errors.As(err, &bindErr))
+	assert.ErrorIs(t, bindErr.Fields[0], ErrTooManyFormKeys)
+}
+
+type testLevel int
+
+func (l *testLevel) UnmarshalParam(param string) error {
+	switch param {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+type testMoney struct {
+	Cents int64
+}
+
+func TestMapForm_Custom(t *testing.T) {
+	RegisterConverter(func(val string) (testMoney, error) {
+		f, err := strconv.ParseFloat(val, 64)
+		return testMoney{Cents: int64(f * 100)}, err
+	})
+
+	var obj struct {
+		IP      net.IP       `form:"ip"`
+		Level   testLevel    `form:"level"`
+		Levels  []testLevel  `form:"levels"`
+		Price   testMoney    `form:"price"`
+		Prices  []*testMoney `form:"prices"`
+		Created time.Time    `form:"created" time_format:"2006-01-02" time_utc:"1"`
+	}
+	form := url.Values{
+		"ip":      {"127.0.0.1"},
+		"level":   {"high"},
+		"levels":  {"low", "high"},
+		"price":   {"9.99"},
+		"prices":  {"1.5"},
+		"created": {"2023-03-01"},
+	}
+	require.NoError(t, mapForm(&obj, form))
+	assert.Equal(t, net.ParseIP("127.0.0.1"), obj.IP)
+	assert.Equal(t, testLevel(2), obj.Level)
+	assert.Equal(t, []testLevel{1, 2}, obj.Levels)
+	assert.Equal(t, testMoney{Cents: 999}, obj.Price)
+	assert.Equal(t, []*testMoney{{Cents: 150}}, obj.Prices)
+	assert.Equal(t, time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), obj.Created)
+
+	form.Set("level", "middle")
+	form.Set("ip", "localhost")
+	err := mapForm(&obj, form)
+	var bindErr *BindingError
+	require.True(t, errors.As(err, &bindErr))
+	require.Len(t, bindErr.Fields, 2)
+	assert.Equal(t, "IP", bindErr.Fields[0].Path)
+	assert.Equal(t, "Level", bindErr.Fields[1].Path)
+	assert.Equal(t, "unknown level", bindErr.Fields[1].Reason)
+}
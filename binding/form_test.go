@@ -0,0 +1,71 @@
+package binding
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type formUser struct {
+	Name string   `form:"name"`
+	Age  int      `form:"age,default=18"`
+	Tags []string `form:"tag"`
+}
+
+func TestFormBinding(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "/?tag=a", strings.NewReader("name=ray&tag=b"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var user formUser
+	assert.NoError(t, FORM.Bind(req, &user))
+	assert.Equal(t, formUser{Name: "ray", Age: 18, Tags: []string{"b", "a"}}, user)
+}
+
+func TestFormPostBinding(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "/?name=query", strings.NewReader("age=20"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var user formUser
+	assert.NoError(t, FORM_POST.Bind(req, &user))
+	assert.Equal(t, formUser{Age: 20}, user)
+}
+
+func TestFormMultipartBinding(t *testing.T) {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	require.NoError(t, mw.WriteField("name", "ray"))
+	for _, name := range []string{"a.txt", "b.txt"} {
+		fw, err := mw.CreateFormFile("files", name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(name))
+		require.NoError(t, err)
+	}
+	fw, err := mw.CreateFormFile("avatar", "avatar.png")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("png"))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req, err := http.NewRequest(http.MethodPost, "/", body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var obj struct {
+		Name   string                  `form:"name"`
+		Avatar *multipart.FileHeader   `form:"avatar"`
+		Files  []*multipart.FileHeader `form:"files"`
+	}
+	assert.NoError(t, FORM_MULTIPART.Bind(req, &obj))
+	assert.Equal(t, "ray", obj.Name)
+	require.NotNil(t, obj.Avatar)
+	assert.Equal(t, "avatar.png", obj.Avatar.Filename)
+	require.Len(t, obj.Files, 2)
+	assert.Equal(t, "a.txt", obj.Files[0].Filename)
+	assert.Equal(t, "b.txt", obj.Files[1].Filename)
+}
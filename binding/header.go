@@ -0,0 +1,34 @@
+package binding
+
+import (
+	"net/http"
+	"net/textproto"
+	"reflect"
+)
+
+type headerBinding struct{}
+
+func (headerBinding) Name() string {
+	return "header"
+}
+
+// Bind (header) binds request headers, repeated headers can be bound to slices
+func (headerBinding) Bind(req *http.Request, obj any) error {
+	if err := mapHeader(obj, req.Header); err != nil {
+		return err
+	}
+	return validate(obj)
+}
+
+func mapHeader(ptr any, h map[string][]string) error {
+	return mappingByPtr(ptr, headerSource(h), "header", SourceHeader)
+}
+
+type headerSource map[string][]string
+
+var _ setter = headerSource(nil)
+
+// TrySet tries to set a value by the canonical form of the header name
+func (hs headerSource) TrySet(value reflect.Value, field reflect.StructField, tagValue string, opt setOptions) (bool, error) {
+	return setByForm(value, field, hs, textproto.CanonicalMIMEHeaderKey(tagValue), opt)
+}
@@ -0,0 +1,29 @@
+package binding
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestHeaderBinding(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	req.Header.Set("x-tenant-id", "42")
+	req.Header.Set("idempotency-key", "abc")
+	req.Header.Add("X-Feature", "a")
+	req.Header.Add("X-Feature", "b")
+
+	var obj struct {
+		TenantID       int64    `header:"x-tenant-id"`
+		IdempotencyKey string   `header:"Idempotency-Key"`
+		ClientVersion  string   `header:"X-Client-Version,default=1.0.0"`
+		Features       []string `header:"X-Feature"`
+	}
+	assert.NoError(t, HEADER.Bind(req, &obj))
+	assert.Equal(t, int64(42), obj.TenantID)
+	assert.Equal(t, "abc", obj.IdempotencyKey)
+	assert.Equal(t, "1.0.0", obj.ClientVersion)
+	assert.Equal(t, []string{"a", "b"}, obj.Features)
+}
@@ -8,7 +8,26 @@ import (
 	"net/http"
 )
 
-type jsonBinding struct{}
+var errTrailingData = errors.New("json: trailing data after the first value")
+
+// JSONOptions configures how the JSON binding decodes the request body
+type JSONOptions struct {
+	// DisallowUnknownFields rejects fields which do not exist in the destination struct
+	DisallowUnknownFields bool
+	// UseNumber decodes numbers into an interface{} as json.Number instead of float64
+	UseNumber bool
+	// DisallowTrailingData rejects anything but whitespace after the first value
+	DisallowTrailingData bool
+}
+
+type jsonBinding struct {
+	opts JSONOptions
+}
+
+// JSONWithOptions returns a JSON binding decoding the body with the options
+func JSONWithOptions(opts JSONOptions) BindingBody {
+	return jsonBinding{opts: opts}
+}
 
 func (j jsonBinding) Name() string {
 	return "json"
@@ -18,17 +37,28 @@ func (j jsonBinding) Bind(request *http.Request, a any) error {
 	if request.Body == nil || a == nil {
 		return errors.New("invalid data")
 	}
-	return decodeJSON(request.Body, a)
+	return decodeJSON(request.Body, a, j.opts)
 }
 
-func (jsonBinding) BindBody(body []byte, obj any) error {
-	return decodeJSON(bytes.NewReader(body), obj)
+func (j jsonBinding) BindBody(body []byte, obj any) error {
+	return decodeJSON(bytes.NewReader(body), obj, j.opts)
 }
 
-func decodeJSON(r io.Reader, obj any) error {
+func decodeJSON(r io.Reader, obj any, opts JSONOptions) error {
 	decoder := json.NewDecoder(r)
+	if opts.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if opts.UseNumber {
+		decoder.UseNumber()
+	}
 	if err := decoder.Decode(obj); err != nil {
-		return err
+		return bodyError(err)
+	}
+	if opts.DisallowTrailingData {
+		if _, err := decoder.Token(); err != io.EOF {
+			return bodyError(errTrailingData)
+		}
 	}
-	return nil
+	return validate(obj)
 }
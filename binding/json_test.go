@@ -0,0 +1,68 @@
+package binding
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestJSONWithOptions(t *testing.T) {
+	type user struct {
+		Name  string `json:"name"`
+		Extra any    `json:"extra"`
+	}
+
+	testCases := []struct {
+		name      string
+		opts      JSONOptions
+		body      string
+		wantErr   string
+		wantExtra any
+	}{
+		{
+			name:      "default",
+			body:      `{"name":"ray","extra":1,"age":18} trailing`,
+			wantExtra: float64(1),
+		},
+		{
+			name:    "disallow unknown fields",
+			opts:    JSONOptions{DisallowUnknownFields: true},
+			body:    `{"name":"ray","age":18}`,
+			wantErr: "body 'age' of field 'age': unknown field",
+		},
+		{
+			name:      "use number",
+			opts:      JSONOptions{UseNumber: true},
+			body:      `{"extra":1}`,
+			wantExtra: json.Number("1"),
+		},
+		{
+			name:    "disallow trailing data",
+			opts:    JSONOptions{DisallowTrailingData: true},
+			body:    `{"name":"ray"}{"name":"ray"}`,
+			wantErr: "body: unexpected data after the JSON value",
+		},
+		{
+			name: "trailing whitespace",
+			opts: JSONOptions{DisallowTrailingData: true},
+			body: "{\"name\":\"ray\"}\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var u user
+			err := JSONWithOptions(tt.opts).BindBody([]byte(tt.body), &u)
+			if tt.wantErr != "" {
+				var bindErr *BindingError
+				require.True(t, errors.As(err, &bindErr))
+				assert.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantExtra, u.Extra)
+		})
+	}
+}
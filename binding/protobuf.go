@@ -29,5 +29,5 @@ func (protobufBinding) BindBody(body []byte, obj any) error {
 	if err := proto.Unmarshal(body, msg); err != nil {
 		return err
 	}
-	return nil
+	return validate(obj)
 }
@@ -0,0 +1,36 @@
+package binding
+
+import (
+	"errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net/http"
+)
+
+type protoJSONBinding struct{}
+
+func (protoJSONBinding) Name() string {
+	return "protojson"
+}
+
+func (b protoJSONBinding) Bind(req *http.Request, obj any) error {
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return b.BindBody(buf, obj)
+}
+
+// BindBody decodes the JSON representation of a protobuf message, the field
+// names can be either the JSON name or the original proto name.
+func (protoJSONBinding) BindBody(body []byte, obj any) error {
+	msg, ok := obj.(proto.Message)
+	if !ok {
+		return errors.New("obj is not ProtoMessage")
+	}
+	if err := protojson.Unmarshal(body, msg); err != nil {
+		return err
+	}
+	return validate(obj)
+}
@@ -10,8 +10,8 @@ func (queryBinding) Name() string {
 
 func (queryBinding) Bind(req *http.Request, obj any) error {
 	values := req.URL.Query()
-	if err := mapForm(obj, values); err != nil {
+	if err := mapQuery(obj, values); err != nil {
 		return err
 	}
-	return nil
+	return validate(obj)
 }
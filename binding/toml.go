@@ -0,0 +1,43 @@
+package binding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/pelletier/go-toml/v2"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type tomlBinding struct{}
+
+func (tomlBinding) Name() string {
+	return "toml"
+}
+
+func (tomlBinding) Bind(req *http.Request, obj any) error {
+	return decodeTOML(req.Body, obj)
+}
+
+func (tomlBinding) BindBody(body []byte, obj any) error {
+	return decodeTOML(bytes.NewReader(body), obj)
+}
+
+func decodeTOML(r io.Reader, obj any) error {
+	decoder := toml.NewDecoder(r)
+	if err := decoder.Decode(obj); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			key := strings.Join(decodeErr.Key(), ".")
+			row, col := decodeErr.Position()
+			fe := &FieldBindError{Path: key, Name: key, Source: SourceBody, Reason: decodeErr.Error(), Err: err}
+			if key == "" {
+				fe.Reason = fmt.Sprintf("malformed TOML at line %d column %d", row, col)
+			}
+			return &BindingError{Fields: []*FieldBindError{fe}}
+		}
+		return bodyError(err)
+	}
+	return validate(obj)
+}
@@ -0,0 +1,15 @@
+package binding
+
+type uriBinding struct{}
+
+func (uriBinding) Name() string {
+	return "uri"
+}
+
+// BindUri binds route parameters, the key of m is the parameter name
+func (uriBinding) BindUri(m map[string][]string, obj any) error {
+	if err := mapURI(obj, m); err != nil {
+		return err
+	}
+	return validate(obj)
+}
@@ -0,0 +1,222 @@
+package binding
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	emailRegex    = regexp.MustCompile(`^[a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+	alphaRegex    = regexp.MustCompile(`^[a-zA-Z]+$`)
+	alphaNumRegex = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	numericRegex  = regexp.MustCompile(`^[-+]?[0-9]+(?:\.[0-9]+)?$`)
+	uuidRegex     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+func init() {
+	for name, rule := range map[string]Rule{
+		"min":      sizeRule(func(c int) bool { return c >= 0 }),
+		"max":      sizeRule(func(c int) bool { return c <= 0 }),
+		"len":      sizeRule(func(c int) bool { return c == 0 }),
+		"gt":       sizeRule(func(c int) bool { return c > 0 }),
+		"gte":      sizeRule(func(c int) bool { return c >= 0 }),
+		"lt":       sizeRule(func(c int) bool { return c < 0 }),
+		"lte":      sizeRule(func(c int) bool { return c <= 0 }),
+		"eq":       isEq,
+		"ne":       func(fl FieldLevel) bool { return !isEq(fl) },
+		"oneof":    isOneOf,
+		"email":    regexRule(emailRegex),
+		"alpha":    regexRule(alphaRegex),
+		"alphanum": regexRule(alphaNumRegex),
+		"numeric":  regexRule(numericRegex),
+		"uuid":     regexRule(uuidRegex),
+		"url":      isURL,
+		"eqfield":  isEqField,
+		"nefield":  func(fl FieldLevel) bool { return !isEqField(fl) },
+		"gtfield":  fieldRule(func(c int) bool { return c > 0 }),
+		"gtefield": fieldRule(func(c int) bool { return c >= 0 }),
+		"ltfield":  fieldRule(func(c int) bool { return c < 0 }),
+		"ltefield": fieldRule(func(c int) bool { return c <= 0 }),
+	} {
+		rules[name] = rule
+	}
+}
+
+// sizeRule compares the size of the field with the param, the size is the
+// length of strings (in runes), slices and maps or the value of numbers.
+func sizeRule(ok func(c int) bool) Rule {
+	return func(fl FieldLevel) bool {
+		c, valid := compareSize(fl.Field, fl.Param)
+		return valid && ok(c)
+	}
+}
+
+func compareSize(v reflect.Value, param string) (int, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		p, err := strconv.Atoi(param)
+		return compareInt(int64(utf8.RuneCountInString(v.String())), int64(p)), err == nil
+	case reflect.Slice, reflect.Map, reflect.Array:
+		p, err := strconv.Atoi(param)
+		return compareInt(int64(v.Len()), int64(p)), err == nil
+	case reflect.Int64:
+		if v.Type() == reflect.TypeOf(time.Duration(0)) {
+			p, err := time.ParseDuration(param)
+			return compareInt(v.Int(), int64(p)), err == nil
+		}
+		fallthrough
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		p, err := strconv.ParseInt(param, 10, 64)
+		return compareInt(v.Int(), p), err == nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		p, err := strconv.ParseUint(param, 10, 64)
+		return compareUint(v.Uint(), p), err == nil
+	case reflect.Float32, reflect.Float64:
+		p, err := strconv.ParseFloat(param, 64)
+		return compareFloat(v.Float(), p), err == nil
+	}
+	return 0, false
+}
+
+// isEq compares strings and bools by value, everything else by size
+func isEq(fl FieldLevel) bool {
+	switch fl.Field.Kind() {
+	case reflect.String:
+		return fl.Field.String() == fl.Param
+	case reflect.Bool:
+		p, err := strconv.ParseBool(fl.Param)
+		return err == nil && fl.Field.Bool() == p
+	}
+	c, valid := compareSize(fl.Field, fl.Param)
+	return valid && c == 0
+}
+
+// isOneOf checks the field is one of the space separated values of the param
+func isOneOf(fl FieldLevel) bool {
+	for _, p := range strings.Fields(fl.Param) {
+		switch fl.Field.Kind() {
+		case reflect.String:
+			if fl.Field.String() == p {
+				return true
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if i, err := strconv.ParseInt(p, 10, 64); err == nil && fl.Field.Int() == i {
+				return true
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if u, err := strconv.ParseUint(p, 10, 64); err == nil && fl.Field.Uint() == u {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func regexRule(re *regexp.Regexp) Rule {
+	return func(fl FieldLevel) bool {
+		return fl.Field.Kind() == reflect.String && re.MatchString(fl.Field.String())
+	}
+}
+
+func isURL(fl FieldLevel) bool {
+	if fl.Field.Kind() != reflect.String {
+		return false
+	}
+	u, err := url.Parse(fl.Field.String())
+	return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+}
+
+// otherField returns the sibling field named by the param
+func otherField(fl FieldLevel) (reflect.Value, bool) {
+	if fl.Parent.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	other, isNil := indirect(fl.Parent.FieldByName(fl.Param))
+	return other, other.IsValid() && !isNil
+}
+
+func isEqField(fl FieldLevel) bool {
+	other, ok := otherField(fl)
+	if !ok || other.Type() != fl.Field.Type() {
+		return false
+	}
+	if t, ok := fl.Field.Interface().(time.Time); ok {
+		return t.Equal(other.Interface().(time.Time))
+	}
+	return reflect.DeepEqual(fl.Field.Interface(), other.Interface())
+}
+
+// fieldRule compares the field with the sibling field named by the param,
+// numbers are compared by value, time.Time by instant and others by size.
+func fieldRule(ok func(c int) bool) Rule {
+	return func(fl FieldLevel) bool {
+		other, found := otherField(fl)
+		if !found || other.Kind() != fl.Field.Kind() {
+			return false
+		}
+
+		var c int
+		switch fl.Field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			c = compareInt(fl.Field.Int(), other.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			c = compareUint(fl.Field.Uint(), other.Uint())
+		case reflect.Float32, reflect.Float64:
+			c = compareFloat(fl.Field.Float(), other.Float())
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			c = compareInt(int64(fl.Field.Len()), int64(other.Len()))
+		case reflect.Struct:
+			t, ok1 := fl.Field.Interface().(time.Time)
+			o, ok2 := other.Interface().(time.Time)
+			if !ok1 || !ok2 {
+				return false
+			}
+			switch {
+			case t.Before(o):
+				c = -1
+			case t.After(o):
+				c = 1
+			}
+		default:
+			return false
+		}
+		return ok(c)
+	}
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
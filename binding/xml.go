@@ -23,7 +23,7 @@ func (xmlBinding) BindBody(body []byte, obj any) error {
 func decodeXML(r io.Reader, obj any) error {
 	decoder := xml.NewDecoder(r)
 	if err := decoder.Decode(obj); err != nil {
-		return err
+		return bodyError(err)
 	}
-	return nil
+	return validate(obj)
 }
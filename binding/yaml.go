@@ -0,0 +1,40 @@
+package binding
+
+import (
+	"bytes"
+	"errors"
+	"gopkg.in/yaml.v3"
+	"io"
+	"net/http"
+)
+
+type yamlBinding struct{}
+
+func (yamlBinding) Name() string {
+	return "yaml"
+}
+
+func (yamlBinding) Bind(req *http.Request, obj any) error {
+	return decodeYAML(req.Body, obj)
+}
+
+func (yamlBinding) BindBody(body []byte, obj any) error {
+	return decodeYAML(bytes.NewReader(body), obj)
+}
+
+func decodeYAML(r io.Reader, obj any) error {
+	decoder := yaml.NewDecoder(r)
+	if err := decoder.Decode(obj); err != nil {
+		// yaml.TypeError lists every value which could not be decoded
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			fields := make([]*FieldBindError, 0, len(typeErr.Errors))
+			for _, msg := range typeErr.Errors {
+				fields = append(fields, &FieldBindError{Source: SourceBody, Reason: msg, Err: err})
+			}
+			return &BindingError{Fields: fields}
+		}
+		return bodyError(err)
+	}
+	return validate(obj)
+}
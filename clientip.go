@@ -0,0 +1,153 @@
+package web
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// 常见云平台设置的客户端 IP 请求头，用于 HttpServer.TrustedPlatform
+// 只有在服务只能通过该平台访问时才可以设置，否则客户端可以伪造请求头
+const (
+	PlatformCloudflare      = "CF-Connecting-IP"
+	PlatformGoogleAppEngine = "X-Appengine-Remote-Addr"
+	PlatformFlyIO           = "Fly-Client-IP"
+	PlatformAkamai          = "True-Client-IP"
+)
+
+// forwardedHeader RFC 7239 定义的 Forwarded 请求头
+const forwardedHeader = "Forwarded"
+
+// SetTrustedProxies 设置可信代理，支持 IP 和 CIDR，例如 10.0.0.1、10.0.0.0/8、::1
+// 只有请求来自可信代理时才会解析 RemoteIPHeaders，传入 nil 表示不信任任何代理
+func (h *HttpServer) SetTrustedProxies(proxies []string) error {
+	cidrs := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return fmt.Errorf("web：无效的可信代理 %s", proxy)
+			}
+			bits := net.IPv6len * 8
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), net.IPv4len*8
+			}
+			cidrs = append(cidrs, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return fmt.Errorf("web：无效的可信代理 %s", proxy)
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	h.trustedCIDRs = cidrs
+	return nil
+}
+
+// isTrustedProxy ip 是否为可信代理
+func (h *HttpServer) isTrustedProxy(ip net.IP) bool {
+	for _, cidr := range h.trustedCIDRs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteIP 返回 TCP 连接的对端 IP，即 http.Request.RemoteAddr 中的 IP
+func (c *Context) RemoteIP() string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(c.Request.RemoteAddr))
+	if err != nil {
+		return strings.TrimSpace(c.Request.RemoteAddr)
+	}
+	return host
+}
+
+// ClientIP 返回客户端的真实 IP
+//  1. 设置了 HttpServer.TrustedPlatform 时使用平台的请求头
+//  2. 请求来自可信代理时，按照 HttpServer.RemoteIPHeaders 的顺序解析请求头
+//     从右向左跳过可信代理，第一个不可信的 IP 即为客户端 IP
+//  3. 否则返回 RemoteIP
+func (c *Context) ClientIP() string {
+	if c.h.TrustedPlatform != "" {
+		if ip := net.ParseIP(strings.TrimSpace(c.Request.Header.Get(c.h.TrustedPlatform))); ip != nil {
+			return ip.String()
+		}
+	}
+
+	remoteIP := c.RemoteIP()
+	ip := net.ParseIP(remoteIP)
+	if ip == nil || !c.h.isTrustedProxy(ip) {
+		return remoteIP
+	}
+
+	for _, header := range c.h.RemoteIPHeaders {
+		chain, ok := forwardedChain(c.Request.Header, header)
+		if !ok {
+			continue
+		}
+		if clientIP, ok := c.h.clientIPFromChain(chain); ok {
+			return clientIP
+		}
+	}
+	return remoteIP
+}
+
+// clientIPFromChain 从右向左跳过可信代理，返回第一个不可信的 IP
+// 链中全部是可信代理时返回最左侧的 IP，链中存在无效 IP 时认为整个请求头无效
+func (h *HttpServer) clientIPFromChain(chain []string) (string, bool) {
+	var clientIP net.IP
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := parseForwardedIP(chain[i])
+		if ip == nil {
+			return "", false
+		}
+		clientIP = ip
+		if !h.isTrustedProxy(ip) {
+			break
+		}
+	}
+	return clientIP.String(), clientIP != nil
+}
+
+// forwardedChain 返回请求头中经过的地址，多个同名请求头按照顺序合并
+func forwardedChain(header http.Header, name string) ([]string, bool) {
+	values := header.Values(name)
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	var chain []string
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			if http.CanonicalHeaderKey(name) == forwardedHeader {
+				element = forwardedFor(element)
+			}
+			chain = append(chain, strings.TrimSpace(element))
+		}
+	}
+	return chain, true
+}
+
+// forwardedFor 解析 Forwarded 请求头中一个元素的 for 参数，例如 for="[2001:db8::1]:4711";proto=https
+func forwardedFor(element string) string {
+	for _, pair := range strings.Split(element, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "for") {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return ""
+}
+
+// parseForwardedIP 解析请求头中的地址，支持携带端口和 IPv6 中括号，unknown 等无效地址返回 nil
+func parseForwardedIP(addr string) net.IP {
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	return net.ParseIP(addr)
+}
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_ClientIP(t *testing.T) {
+	testCases := []struct {
+		name       string
+		proxies    []string
+		ipHeaders  []string
+		platform   string
+		remoteAddr string
+		header     http.Header
+		wantIP     string
+	}{
+		{
+			name:       "no proxy",
+			remoteAddr: "203.0.113.7:52100",
+			wantIP:     "203.0.113.7",
+		},
+		{
+			name:       "untrusted remote ignores headers",
+			remoteAddr: "203.0.113.7:52100",
+			header:     http.Header{"X-Forwarded-For": {"1.1.1.1"}, "X-Real-Ip": {"2.2.2.2"}},
+			wantIP:     "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:8080",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}},
+			wantIP:     "198.51.100.1",
+		},
+		{
+			name:       "spoofed chain skips only trusted proxies",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:8080",
+			// 客户端伪造了 1.1.1.1，198.51.100.1 为真实客户端，由可信代理 10.0.0.3 追加
+			header: http.Header{"X-Forwarded-For": {"1.1.1.1, 198.51.100.1, 10.0.0.3"}},
+			wantIP: "198.51.100.1",
+		},
+		{
+			name:       "multiple header lines",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:8080",
+			header:     http.Header{"X-Forwarded-For": {"1.1.1.1", "198.51.100.1, 10.0.0.3"}},
+			wantIP:     "198.51.100.1",
+		},
+		{
+			name:       "all trusted returns leftmost",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:8080",
+			header:     http.Header{"X-Forwarded-For": {"10.0.0.5, 10.0.0.3"}},
+			wantIP:     "10.0.0.5",
+		},
+		{
+			name:       "invalid chain falls back to next header",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:8080",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1, evil"}, "X-Real-Ip": {"198.51.100.2"}},
+			wantIP:     "198.51.100.2",
+		},
+		{
+			name:       "invalid headers fall back to remote",
+			proxies:    []string{"10.0.0.2"},
+			remoteAddr: "10.0.0.2:8080",
+			header:     http.Header{"X-Forwarded-For": {"unknown"}},
+			wantIP:     "10.0.0.2",
+		},
+		{
+			name:       "ipv6 proxy",
+			proxies:    []string{"::1"},
+			remoteAddr: "[::1]:8080",
+			header:     http.Header{"X-Forwarded-For": {"2001:db8::1"}},
+			wantIP:     "2001:db8::1",
+		},
+		{
+			name:       "forwarded",
+			proxies:    []string{"10.0.0.0/8"},
+			ipHeaders:  []string{"Forwarded", "X-Forwarded-For"},
+			remoteAddr: "10.0.0.2:8080",
+			header: http.Header{
+				"Forwarded":       {`for=1.1.1.1, for="[2001:db8::1]:4711";proto=https, for=10.0.0.3;by=10.0.0.2`},
+				"X-Forwarded-For": {"198.51.100.1"},
+			},
+			wantIP: "2001:db8::1",
+		},
+		{
+			name:       "forwarded obfuscated",
+			proxies:    []string{"10.0.0.0/8"},
+			ipHeaders:  []string{"Forwarded", "X-Forwarded-For"},
+			remoteAddr: "10.0.0.2:8080",
+			header: http.Header{
+				"Forwarded":       {"for=_hidden"},
+				"X-Forwarded-For": {"198.51.100.1"},
+			},
+			wantIP: "198.51.100.1",
+		},
+		{
+			name:       "trusted platform",
+			platform:   PlatformCloudflare,
+			remoteAddr: "203.0.113.7:52100",
+			header:     http.Header{"Cf-Connecting-Ip": {"198.51.100.9"}, "X-Forwarded-For": {"1.1.1.1"}},
+			wantIP:     "198.51.100.9",
+		},
+		{
+			name:       "invalid platform header",
+			platform:   PlatformCloudflare,
+			remoteAddr: "203.0.113.7:52100",
+			header:     http.Header{"Cf-Connecting-Ip": {"evil"}},
+			wantIP:     "203.0.113.7",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := New()
+			require.NoError(t, server.SetTrustedProxies(tt.proxies))
+			if tt.ipHeaders != nil {
+				server.RemoteIPHeaders = tt.ipHeaders
+			}
+			server.TrustedPlatform = tt.platform
+
+			var clientIP string
+			server.GET("/", func(ctx *Context) {
+				clientIP = ctx.ClientIP()
+			})
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = tt.remoteAddr
+			for key, values := range tt.header {
+				request.Header[key] = values
+			}
+			server.ServeHTTP(httptest.NewRecorder(), request)
+			assert.Equal(t, tt.wantIP, clientIP)
+		})
+	}
+}
+
+func TestHttpServer_SetTrustedProxies(t *testing.T) {
+	server := New()
+	assert.NoError(t, server.SetTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16", "::1", "fd00::/8"}))
+	assert.Len(t, server.trustedCIDRs, 4)
+	assert.Error(t, server.SetTrustedProxies([]string{"10.0.0.300"}))
+	assert.Error(t, server.SetTrustedProxies([]string{"10.0.0.0/33"}))
+}
@@ -2,11 +2,14 @@ package web
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
 	"github.com/killlowkey/web/binding"
+	"github.com/killlowkey/web/render"
+	"io"
+	"io/fs"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"sync"
 )
 
 // Context 处理请求输入输出
@@ -21,13 +24,27 @@ type Context struct {
 	RespStatus int    // 保存响应状态码
 	RespData   []byte
 
+	// UserValues 请求范围内的值，使用 Set、Get 读写，保证并发安全
 	UserValues map[string]any
+	Errors     []error // 处理请求过程中发生的错误
+	mu         sync.RWMutex
 
 	h          *HttpServer
 	queryCache url.Values
+	// written 响应已经直接写入 Writer，flush 时不再回写
+	written bool
+	// buffered 要求响应缓存到 RespData，不直接写入 Writer
+	buffered bool
+	// forwards Forward 转发的次数
+	forwards int
+	// trailers 响应 body 之后写回的 trailer，使用 SetTrailer 设置
+	trailers http.Header
+	// beforeWrite 写回状态码之前执行的回调，使用 BeforeWrite 注册
+	beforeWrite []func(ctx *Context)
 }
 
-// reset 重置 Context，从 Context Pool 获取后需要进行重置
+// reset 重置 Context，从 Context Pool 获取后和归还前都需要进行重置
+// 归还前重置可以及时释放请求范围内的值，避免被下一个请求读取
 func (c *Context) reset() {
 	c.Request = nil
 	c.Writer = nil
@@ -38,7 +55,18 @@ func (c *Context) reset() {
 	c.Route = ""
 	c.h = nil
 	c.queryCache = nil
+	c.mu.Lock()
 	c.UserValues = nil
+	c.mu.Unlock()
+	for i := range c.Errors {
+		c.Errors[i] = nil
+	}
+	c.Errors = c.Errors[:0]
+	c.written = false
+	c.buffered = false
+	c.forwards = 0
+	c.trailers = nil
+	c.beforeWrite = nil
 }
 
 // ===============================
@@ -74,6 +102,29 @@ func (c *Context) GetQueryArray(key string) (values []string, ok bool) {
 	return
 }
 
+// FormFile 获取 multipart 表单中 name 对应的第一个文件
+func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	files := form.File[name]
+	if len(files) == 0 {
+		return nil, http.ErrMissingFile
+	}
+	return files[0], nil
+}
+
+// MultipartForm 解析 multipart 表单，超出 binding.MaxMultipartMemory 的文件保存到临时文件中
+func (c *Context) MultipartForm() (*multipart.Form, error) {
+	if c.Request.MultipartForm == nil {
+		if err := c.Request.ParseMultipartForm(binding.MaxMultipartMemory); err != nil {
+			return nil, err
+		}
+	}
+	return c.Request.MultipartForm, nil
+}
+
 func (c *Context) initQueryCache() {
 	if c.queryCache == nil {
 		if c.Request != nil {
@@ -84,9 +135,17 @@ func (c *Context) initQueryCache() {
 	}
 }
 
-// BindJSON 绑定请求 body
+// BindWith 使用指定的 binding 绑定请求
+func (c *Context) BindWith(obj any, b binding.Binding) error {
+	return b.Bind(c.Request, obj)
+}
+
+// BindJSON 绑定请求 body，使用 HttpServer.JSONOptions 进行解析
 // TODO request 的 body 只能读取一次，此处仅为演示，后续需要修改
 func (c *Context) BindJSON(val any) error {
+	if c.h != nil {
+		return binding.JSONWithOptions(c.h.JSONOptions).Bind(c.Request, val)
+	}
 	return binding.JSON.Bind(c.Request, val)
 }
 
@@ -100,11 +159,62 @@ func (c *Context) BindQuery(obj any) error {
 	return binding.QUERY.Bind(c.Request, obj)
 }
 
+// BindForm 从请求的查询参数、表单以及 multipart 表单绑定
+func (c *Context) BindForm(obj any) error {
+	return binding.FORM.Bind(c.Request, obj)
+}
+
+// BindFormPost 仅从请求 body 中的 application/x-www-form-urlencoded 表单绑定
+func (c *Context) BindFormPost(obj any) error {
+	return binding.FORM_POST.Bind(c.Request, obj)
+}
+
+// BindFormMultipart 从 multipart/form-data 表单绑定，支持绑定上传文件到
+// *multipart.FileHeader 与 []*multipart.FileHeader 字段
+func (c *Context) BindFormMultipart(obj any) error {
+	return binding.FORM_MULTIPART.Bind(c.Request, obj)
+}
+
+// BindUri 从路由参数绑定，字段使用 uri 标签
+// 绑定路由为 /user/:id，实际请求 /user/1，`uri:"id"` 字段得到 1
+func (c *Context) BindUri(obj any) error {
+	m := make(map[string][]string, len(c.Params))
+	for _, p := range c.Params {
+		m[p.key] = []string{p.value}
+	}
+	return binding.URI.BindUri(m, obj)
+}
+
+// BindHeader 从请求头绑定，字段使用 header 标签，例如 `header:"X-Tenant-Id"`
+func (c *Context) BindHeader(obj any) error {
+	return binding.HEADER.Bind(c.Request, obj)
+}
+
+// BindYAML 从请求 YAML 进行绑定
+func (c *Context) BindYAML(obj any) error {
+	return binding.YAML.Bind(c.Request, obj)
+}
+
+// BindTOML 从请求 TOML 进行绑定
+func (c *Context) BindTOML(obj any) error {
+	return binding.TOML.Bind(c.Request, obj)
+}
+
+// BindMsgPack 从请求 MessagePack 进行绑定
+func (c *Context) BindMsgPack(obj any) error {
+	return binding.MSGPACK.Bind(c.Request, obj)
+}
+
 // BindProtobuf 从请求的 protobuf 绑定
 func (c *Context) BindProtobuf(obj any) error {
 	return binding.PROTOBUF.Bind(c.Request, obj)
 }
 
+// BindProtoJSON 从请求 JSON 绑定 protobuf，适用于使用 JSON 访问 protobuf 接口的客户端
+func (c *Context) BindProtoJSON(obj any) error {
+	return binding.PROTOJSON.Bind(c.Request, obj)
+}
+
 // =================================
 // ======== Response register ======
 // =================================
@@ -122,52 +232,240 @@ func (c *Context) WriteWithStatus(status int, data []byte) {
 	c.RespData = data
 }
 
+// Header 等同于 SetHeader，val 为空时删除响应头
 func (c *Context) Header(key, val string) {
-	if val == "" {
-		c.Writer.Header().Del(key)
-		return
-	}
-	c.Writer.Header().Add(key, val)
+	c.SetHeader(key, val)
 }
 
 func (c *Context) String(code int, format string, values ...any) {
-	msg := format
-	if len(values) != 0 {
-		msg = fmt.Sprintf(format, values...)
-	}
-	c.WriteWithStatus(code, []byte(msg))
+	c.Render(code, render.String{Format: format, Data: values})
 }
 
 func (c *Context) JSON(status int, val any) {
-	if val == nil {
-		c.WriteWithStatus(http.StatusInternalServerError, []byte("val 为 nil"))
+	c.Render(status, render.JSON{Data: val})
+}
+
+// IndentedJSON 将 val 序列化为带缩进的 JSON 写回响应，便于调试阅读
+func (c *Context) IndentedJSON(status int, val any) {
+	c.Render(status, render.IndentedJSON{Data: val})
+}
+
+// PureJSON 将 val 序列化为 JSON 写回响应，不转义 HTML 字符
+func (c *Context) PureJSON(status int, val any) {
+	c.Render(status, render.PureJSON{Data: val})
+}
+
+// SecureJSON 将 val 序列化为 JSON 写回响应，数组添加 HttpServer.SecureJSONPrefix 前缀
+func (c *Context) SecureJSON(status int, val any) {
+	c.Render(status, render.SecureJSON{Prefix: c.h.SecureJSONPrefix, Data: val})
+}
+
+// AsciiJSON 将 val 序列化为 JSON 写回响应，非 ASCII 字符转义为 \uXXXX
+func (c *Context) AsciiJSON(status int, val any) {
+	c.Render(status, render.AsciiJSON{Data: val})
+}
+
+// JSONP 使用查询参数 callback 包裹 JSON 写回响应，未携带 callback 时按照 JSON 写回
+// callback 不合法时响应 400
+func (c *Context) JSONP(status int, val any) {
+	callback := c.Query("callback")
+	if callback != "" && !render.ValidCallback(callback) {
+		c.Error(render.ErrInvalidCallback)
+		c.String(http.StatusBadRequest, invalidCallback)
 		return
 	}
+	c.Render(status, render.JSONP{Callback: callback, Data: val})
+}
 
-	if data, err := json.Marshal(val); err != nil {
-		c.WriteWithStatus(http.StatusInternalServerError, []byte(err.Error()))
-	} else {
-		c.Header("Content-type", "application/json; charset=utf-8")
-		c.WriteWithStatus(status, data)
-	}
+// XML 将 val 序列化为 XML 写回响应
+func (c *Context) XML(status int, val any) {
+	c.Render(status, render.XML{Data: val})
+}
+
+// YAML 将 val 序列化为 YAML 写回响应
+func (c *Context) YAML(status int, val any) {
+	c.Render(status, render.YAML{Data: val})
+}
+
+// TOML 将 val 序列化为 TOML 写回响应
+func (c *Context) TOML(status int, val any) {
+	c.Render(status, render.TOML{Data: val})
+}
+
+// MsgPack 将 val 编码为 MessagePack 写回响应
+func (c *Context) MsgPack(status int, val any) {
+	c.Render(status, render.MsgPack{Data: val})
+}
+
+// ProtoBuf 将 proto.Message 序列化为 protobuf 写回响应
+func (c *Context) ProtoBuf(status int, msg any) {
+	c.Render(status, render.ProtoBuf{Data: msg})
+}
+
+// ProtoJSON 将 proto.Message 按照 protobuf 的 JSON 映射规则写回响应
+func (c *Context) ProtoJSON(status int, msg any) {
+	c.Render(status, render.ProtoJSON{Data: msg})
 }
 
 // HTML 渲染 HTML 模版
 func (c *Context) HTML(code int, name string, obj any) {
-	// TODO 错误示例，为了节省时间
-	var data = &bytes.Buffer{}
+	var r render.Render = render.HTML{Name: name, Data: obj}
+	if c.h.HTMLRender != nil {
+		r = c.h.HTMLRender.Instance(name, obj)
+	}
+	c.stream(code, r)
+}
+
+// BufferResponse 要求响应缓存到 RespData 中，由 flush 统一写回
+// 需要在 next 返回后修改响应的 Middleware 应在调用 next 前调用
+func (c *Context) BufferResponse() {
+	c.buffered = true
+}
+
+// Written 响应是否已经直接写入 Writer，写入后无法再修改状态码和响应
+func (c *Context) Written() bool {
+	return c.written
+}
 
-	if err := c.h.templ.ExecuteTemplate(data, name, obj); err != nil {
-		c.WriteWithStatus(http.StatusInternalServerError, []byte(err.Error()))
+// stream 将渲染结果直接写入 Writer，避免缓存整个响应，Middleware 要求缓存时使用 Render
+// 写入第一个字节前发生错误时响应 500，之后发生的错误只能记录到 Errors 中，响应被截断
+func (c *Context) stream(code int, r render.Render) {
+	if c.buffered || !bodyAllowedForStatus(code) {
+		c.Render(code, r)
 		return
 	}
+	c.Status(code)
 
+	w := newStreamWriter(c)
+	if err := r.Render(w); err != nil {
+		c.Error(err)
+		if c.written {
+			return
+		}
+		c.internalError()
+		return
+	}
+	w.writeHeader()
+}
+
+// Render 所有响应的统一出口，渲染结果缓存到 RespData，由 flush 写回响应
+// 渲染失败时记录到 Errors 中，并响应 500
+func (c *Context) Render(code int, r render.Render) {
 	c.Status(code)
-	c.Header("Content-type", "text/html; charset=utf-8")
-	c.Write(data.Bytes())
+
+	if !bodyAllowedForStatus(code) {
+		r.WriteContentType(c.Writer)
+		c.RespData = nil
+		return
+	}
+
+	w := &renderWriter{ResponseWriter: c.Writer}
+	if err := r.Render(w); err != nil {
+		c.Error(err)
+		c.internalError()
+		return
+	}
+	c.RespData = w.buf.Bytes()
+}
+
+// internalError 渲染失败时响应 500，清除 Render 已经设置的响应头
+func (c *Context) internalError() {
+	header := c.Writer.Header()
+	header.Del("Content-Length")
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	c.WriteWithStatus(http.StatusInternalServerError, []byte(internalError))
+}
+
+// Error 记录处理请求过程中发生的错误，交由 Middleware 统一处理
+func (c *Context) Error(err error) {
+	if err == nil {
+		panic("web：Context.Error 传入 err 为 nil")
+	}
+	c.Errors = append(c.Errors, err)
+}
+
+// renderWriter 缓存 render.Render 写入的数据，响应头直接写入到 http.ResponseWriter
+type renderWriter struct {
+	http.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *renderWriter) Write(data []byte) (int, error) {
+	return w.buf.Write(data)
+}
+
+// WriteHeader 状态码由 Context.RespStatus 决定，flush 时统一写回
+func (w *renderWriter) WriteHeader(int) {}
+
+// streamWriter 第一次写入时写回状态码，之后的数据直接写入 http.ResponseWriter
+// 响应已经写入后 Context.Written 返回 true，flush 不再回写
+type streamWriter struct {
+	http.ResponseWriter
+	ctx *Context
+}
+
+func newStreamWriter(c *Context) *streamWriter {
+	return &streamWriter{ResponseWriter: c.Writer, ctx: c}
+}
+
+func (w *streamWriter) Write(data []byte) (int, error) {
+	w.writeHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteHeader 更新 Context.RespStatus 并写回状态码，例如 http.ServeContent 响应 206、304
+func (w *streamWriter) WriteHeader(code int) {
+	if w.ctx.written {
+		return
+	}
+	w.ctx.RespStatus = code
+	w.writeHeader()
+}
+
+func (w *streamWriter) writeHeader() {
+	if w.ctx.written {
+		return
+	}
+	w.ctx.runBeforeWrite()
+	w.ctx.written = true
+	w.ResponseWriter.WriteHeader(w.ctx.RespStatus)
 }
 
 // File 文件服务器
 func (c *Context) File(filepath string) {
-	http.ServeFile(c.Writer, c.Request, filepath)
+	http.ServeFile(newStreamWriter(c), c.Request, filepath)
+}
+
+// FileAttachment 以附件的形式下载文件，浏览器保存的文件名为 filename
+// 文件名包含非 ASCII 字符时按照 RFC 5987 编码，同时提供 ASCII 文件名兼容旧的客户端
+func (c *Context) FileAttachment(filepath, filename string) {
+	c.Writer.Header().Set("Content-Disposition", contentDisposition("attachment", filename))
+	c.File(filepath)
+}
+
+// FileFromFS 从 fs.FS 中响应文件，支持 Range、If-Modified-Since 等请求头
+// filepath 为目录或者不存在时响应 404
+func (c *Context) FileFromFS(filepath string, fsys fs.FS) {
+	name, ok := cleanName(filepath)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	StaticConfig{FS: fsys}.serveFile(c, name)
+}
+
+// Data 使用指定的 contentType 写回原始数据
+func (c *Context) Data(code int, contentType string, data []byte) {
+	c.Render(code, render.Data{ContentType: contentType, Data: data})
+}
+
+// DataFromReader 将 reader 中的数据直接写回响应，不缓存到 RespData
+// contentLength 小于 0 时不设置 Content-Length，headers 为额外的响应头
+func (c *Context) DataFromReader(code int, contentLength int64, contentType string, reader io.Reader, headers map[string]string) {
+	c.stream(code, render.Reader{
+		ContentType:   contentType,
+		ContentLength: contentLength,
+		Reader:        reader,
+		Headers:       headers,
+	})
 }
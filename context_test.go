@@ -1 +1,704 @@
 package web
+
+import (
+	"bytes"
+	"errors"
+	"github.com/killlowkey/web/render"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+	"gopkg.in/yaml.v3"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"testing/iotest"
+	"time"
+)
+
+func TestContext_BindUri(t *testing.T) {
+	type article struct {
+		Author string    `uri:"author"`
+		ID     int       `uri:"id"`
+		Page   int       `uri:"page,default=1"`
+		Date   time.Time `uri:"date" time_format:"2006-01-02" time_utc:"1"`
+	}
+
+	server := New()
+	var got article
+	var bindErr error
+	server.GET("/articles/:author/:date/:id", func(ctx *Context) {
+		bindErr = ctx.BindUri(&got)
+	})
+
+	testCases := []struct {
+		name    string
+		path    string
+		wantErr bool
+		want    article
+	}{
+		{
+			name: "ok",
+			path: "/articles/ray/2023-03-01/10",
+			want: article{
+				Author: "ray",
+				ID:     10,
+				Page:   1,
+				Date:   time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "invalid id",
+			path:    "/articles/ray/2023-03-01/abc",
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			path:    "/articles/ray/20230301/10",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, bindErr = article{}, nil
+			request, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			assert.NoError(t, err)
+			server.ServeHTTP(httptest.NewRecorder(), request)
+			if tt.wantErr {
+				assert.Error(t, bindErr)
+				return
+			}
+			assert.NoError(t, bindErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestContext_BodyFormats(t *testing.T) {
+	type config struct {
+		Name    string   `json:"name" yaml:"name" toml:"name" codec:"name" binding:"required"`
+		Port    int      `json:"port" yaml:"port" toml:"port" codec:"port"`
+		Servers []string `json:"servers" yaml:"servers" toml:"servers" codec:"servers"`
+	}
+	want := config{Name: "web", Port: 8080, Servers: []string{"a", "b"}}
+
+	var mh codec.MsgpackHandle
+	var msgpackBody []byte
+	assert.NoError(t, codec.NewEncoderBytes(&msgpackBody, &mh).Encode(want))
+
+	server := New()
+	server.POST("/yaml", func(ctx *Context) {
+		var c config
+		if err := ctx.BindYAML(&c); err != nil {
+			ctx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx.YAML(http.StatusOK, c)
+	})
+	server.POST("/toml", func(ctx *Context) {
+		var c config
+		if err := ctx.BindTOML(&c); err != nil {
+			ctx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx.TOML(http.StatusOK, c)
+	})
+	server.POST("/msgpack", func(ctx *Context) {
+		var c config
+		if err := ctx.BindMsgPack(&c); err != nil {
+			ctx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx.MsgPack(http.StatusOK, c)
+	})
+
+	testCases := []struct {
+		name            string
+		path            string
+		body            []byte
+		wantCode        int
+		wantContentType string
+		decode          func(data []byte, c *config) error
+	}{
+		{
+			name:            "yaml",
+			path:            "/yaml",
+			body:            []byte("name: web\nport: 8080\nservers: [a, b]\n"),
+			wantCode:        http.StatusOK,
+			wantContentType: "application/yaml; charset=utf-8",
+			decode: func(data []byte, c *config) error {
+				return yaml.Unmarshal(data, c)
+			},
+		},
+		{
+			name:            "toml",
+			path:            "/toml",
+			body:            []byte("name = 'web'\nport = 8080\nservers = ['a', 'b']\n"),
+			wantCode:        http.StatusOK,
+			wantContentType: "application/toml; charset=utf-8",
+			decode: func(data []byte, c *config) error {
+				return toml.Unmarshal(data, c)
+			},
+		},
+		{
+			name:            "msgpack",
+			path:            "/msgpack",
+			body:            msgpackBody,
+			wantCode:        http.StatusOK,
+			wantContentType: "application/msgpack",
+			decode: func(data []byte, c *config) error {
+				return codec.NewDecoderBytes(data, &mh).Decode(c)
+			},
+		},
+		{
+			name:     "yaml validation",
+			path:     "/yaml",
+			body:     []byte("port: 8080\n"),
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, tt.path, bytes.NewReader(tt.body))
+			assert.NoError(t, err)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			if tt.decode == nil {
+				return
+			}
+			assert.Equal(t, tt.wantContentType, response.Header().Get("Content-Type"))
+			var got config
+			assert.NoError(t, tt.decode(response.Body.Bytes(), &got))
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestContext_Render(t *testing.T) {
+	server := New()
+	var errs []error
+	server.Use(func(next HandleFunc) HandleFunc {
+		return func(ctx *Context) {
+			next(ctx)
+			errs = append([]error(nil), ctx.Errors...)
+		}
+	})
+	server.GET("/json", func(ctx *Context) {
+		ctx.Header("Content-Type", "application/vnd.api+json")
+		ctx.JSON(http.StatusOK, H{"name": "ray"})
+	})
+	server.GET("/string", func(ctx *Context) {
+		ctx.String(http.StatusOK, "hello, %s", "ray")
+	})
+	server.GET("/no-content", func(ctx *Context) {
+		ctx.JSON(http.StatusNoContent, H{"name": "ray"})
+	})
+	server.GET("/json-error", func(ctx *Context) {
+		ctx.JSON(http.StatusOK, H{"ch": make(chan int)})
+	})
+	server.GET("/html", func(ctx *Context) {
+		ctx.HTML(http.StatusOK, "index.tmpl", nil)
+	})
+
+	testCases := []struct {
+		name            string
+		path            string
+		wantCode        int
+		wantBody        string
+		wantContentType []string
+		wantErr         bool
+	}{
+		{
+			name:            "keep content type",
+			path:            "/json",
+			wantCode:        http.StatusOK,
+			wantBody:        `{"name":"ray"}`,
+			wantContentType: []string{"application/vnd.api+json"},
+		},
+		{
+			name:            "string",
+			path:            "/string",
+			wantCode:        http.StatusOK,
+			wantBody:        "hello, ray",
+			wantContentType: []string{"text/plain; charset=utf-8"},
+		},
+		{
+			name:            "no content",
+			path:            "/no-content",
+			wantCode:        http.StatusNoContent,
+			wantContentType: []string{"application/json; charset=utf-8"},
+		},
+		{
+			name:            "render error",
+			path:            "/json-error",
+			wantCode:        http.StatusInternalServerError,
+			wantBody:        internalError,
+			wantContentType: []string{"text/plain; charset=utf-8"},
+			wantErr:         true,
+		},
+		{
+			name:            "html without templates",
+			path:            "/html",
+			wantCode:        http.StatusInternalServerError,
+			wantBody:        internalError,
+			wantContentType: []string{"text/plain; charset=utf-8"},
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			assert.NoError(t, err)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+			assert.Equal(t, tt.wantContentType, response.Header().Values("Content-Type"))
+			assert.Equal(t, tt.wantErr, len(errs) > 0)
+		})
+	}
+}
+
+func TestContext_ProtoBuf(t *testing.T) {
+	want := &apipb.Api{Name: "web", Version: "v1", Methods: []*apipb.Method{{Name: "GetUser"}}}
+
+	server := New()
+	server.POST("/protobuf", func(ctx *Context) {
+		api := &apipb.Api{}
+		if err := ctx.BindProtobuf(api); err != nil {
+			ctx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx.ProtoBuf(http.StatusOK, api)
+	})
+	server.POST("/protojson", func(ctx *Context) {
+		api := &apipb.Api{}
+		if err := ctx.BindProtoJSON(api); err != nil {
+			ctx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx.ProtoJSON(http.StatusOK, api)
+	})
+	server.GET("/invalid", func(ctx *Context) {
+		ctx.ProtoBuf(http.StatusOK, H{"name": "web"})
+	})
+
+	protobufBody, err := proto.Marshal(want)
+	assert.NoError(t, err)
+	protojsonBody, err := protojson.Marshal(want)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name            string
+		method          string
+		path            string
+		body            []byte
+		wantCode        int
+		wantContentType string
+		unmarshal       func([]byte, proto.Message) error
+	}{
+		{
+			name:            "protobuf",
+			method:          http.MethodPost,
+			path:            "/protobuf",
+			body:            protobufBody,
+			wantCode:        http.StatusOK,
+			wantContentType: "application/x-protobuf",
+			unmarshal:       proto.Unmarshal,
+		},
+		{
+			name:            "protojson",
+			method:          http.MethodPost,
+			path:            "/protojson",
+			body:            protojsonBody,
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			unmarshal:       protojson.Unmarshal,
+		},
+		{
+			name:     "not proto message",
+			method:   http.MethodGet,
+			path:     "/invalid",
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(tt.method, tt.path, bytes.NewReader(tt.body))
+			assert.NoError(t, err)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			if tt.unmarshal == nil {
+				return
+			}
+			assert.Equal(t, tt.wantContentType, response.Header().Get("Content-Type"))
+			got := &apipb.Api{}
+			assert.NoError(t, tt.unmarshal(response.Body.Bytes(), got))
+			assert.True(t, proto.Equal(want, got))
+		})
+	}
+}
+
+func TestContext_JSONVariants(t *testing.T) {
+	server := New()
+	data := H{"html": "<b>&</b>", "lang": "中文😀"}
+	server.GET("/indented", func(ctx *Context) {
+		ctx.IndentedJSON(http.StatusOK, H{"name": "ray"})
+	})
+	server.GET("/pure", func(ctx *Context) {
+		ctx.PureJSON(http.StatusOK, data)
+	})
+	server.GET("/secure/array", func(ctx *Context) {
+		ctx.SecureJSON(http.StatusOK, []string{"ray"})
+	})
+	server.GET("/secure/object", func(ctx *Context) {
+		ctx.SecureJSON(http.StatusOK, H{"name": "ray"})
+	})
+	server.GET("/ascii", func(ctx *Context) {
+		ctx.AsciiJSON(http.StatusOK, data)
+	})
+	server.GET("/jsonp", func(ctx *Context) {
+		ctx.JSONP(http.StatusOK, H{"name": "ray"})
+	})
+
+	testCases := []struct {
+		name            string
+		path            string
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "indented",
+			path:            "/indented",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        "{\n    \"name\": \"ray\"\n}",
+		},
+		{
+			name:            "pure",
+			path:            "/pure",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"html":"<b>&</b>","lang":"中文😀"}` + "\n",
+		},
+		{
+			name:            "secure array",
+			path:            "/secure/array",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `while(1);["ray"]`,
+		},
+		{
+			name:            "secure object",
+			path:            "/secure/object",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"name":"ray"}`,
+		},
+		{
+			name:            "ascii",
+			path:            "/ascii",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `{"html":"\u003cb\u003e\u0026\u003c/b\u003e","lang":"\u4e2d\u6587\ud83d\ude00"}`,
+		},
+		{
+			name:            "jsonp",
+			path:            "/jsonp?callback=app.handle",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/javascript; charset=utf-8",
+			wantBody:        `/**/app.handle({"name":"ray"});`,
+		},
+		{
+			name:            "jsonp without callback",
+			path:            "/jsonp",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"name":"ray"}`,
+		},
+		{
+			name:            "jsonp invalid callback",
+			path:            "/jsonp?callback=alert(1)//",
+			wantCode:        http.StatusBadRequest,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        invalidCallback,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			assert.NoError(t, err)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantContentType, response.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, response.Body.String())
+		})
+	}
+}
+
+// upperCodec 将序列化结果转为大写，用于验证 render.Codec 可以全局替换
+type upperCodec struct {
+	render.JSONCodec
+}
+
+func (c upperCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.JSONCodec.Marshal(v)
+	return bytes.ToUpper(data), err
+}
+
+func TestContext_JSONCodec(t *testing.T) {
+	origin := render.Codec
+	render.Codec = upperCodec{JSONCodec: origin}
+	defer func() {
+		render.Codec = origin
+	}()
+
+	server := New()
+	server.GET("/user", func(ctx *Context) {
+		ctx.JSON(http.StatusOK, H{"name": "ray"})
+	})
+	request, err := http.NewRequest(http.MethodGet, "/user", nil)
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+	assert.Equal(t, `{"NAME":"RAY"}`, response.Body.String())
+}
+
+func TestContext_HTMLStream(t *testing.T) {
+	templ := template.Must(template.New("").Parse(
+		`{{define "user"}}<p>{{.name}}</p>{{end}}{{define "broken"}}<p>{{.name}}</p>{{call .fail}}{{end}}`))
+	data := H{
+		"name": "ray",
+		"fail": func() (string, error) { return "", errors.New("boom") },
+	}
+	// upper 缓存响应后将其转为大写
+	upper := func(next HandleFunc) HandleFunc {
+		return func(ctx *Context) {
+			ctx.BufferResponse()
+			next(ctx)
+			ctx.RespData = bytes.ToUpper(ctx.RespData)
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		middlewares []Middleware
+		tmpl        string
+		wantWritten bool
+		wantCode    int
+		wantBody    string
+		wantErrs    int
+	}{
+		{
+			name:        "stream",
+			tmpl:        "user",
+			wantWritten: true,
+			wantCode:    http.StatusOK,
+			wantBody:    "<p>ray</p>",
+		},
+		{
+			name:        "buffered by middleware",
+			middlewares: []Middleware{upper},
+			tmpl:        "user",
+			wantCode:    http.StatusOK,
+			wantBody:    "<P>RAY</P>",
+		},
+		{
+			name:     "error before write",
+			tmpl:     "missing",
+			wantCode: http.StatusInternalServerError,
+			wantBody: internalError,
+			wantErrs: 1,
+		},
+		{
+			name:        "error midway",
+			tmpl:        "broken",
+			wantWritten: true,
+			wantCode:    http.StatusOK,
+			wantBody:    "<p>ray</p>",
+			wantErrs:    1,
+		},
+		{
+			name:        "buffered error midway",
+			middlewares: []Middleware{upper},
+			tmpl:        "broken",
+			wantCode:    http.StatusInternalServerError,
+			wantBody:    "500 INTERNAL SERVER ERROR",
+			wantErrs:    1,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := New()
+			server.SetHTMLTemplate(templ)
+			server.Use(errorHandle())
+			server.Use(tt.middlewares...)
+
+			var (
+				written bool
+				errs    int
+			)
+			response := httptest.NewRecorder()
+			server.GET("/", func(ctx *Context) {
+				ctx.HTML(http.StatusOK, tt.tmpl, data)
+				written, errs = ctx.Written(), len(ctx.Errors)
+				// 流式渲染时响应已经写入 Writer，不会等到 flush
+				assert.Equal(t, written, response.Body.Len() > 0)
+			})
+
+			request, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantWritten, written)
+			assert.Equal(t, tt.wantErrs, errs)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+		})
+	}
+}
+
+func TestContext_Data(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "report.txt")
+	require.NoError(t, os.WriteFile(file, []byte("report"), 0o644))
+	files := fstest.MapFS{"docs/readme.txt": {Data: []byte("readme")}}
+
+	testCases := []struct {
+		name        string
+		handler     HandleFunc
+		wantWritten bool
+		wantCode    int
+		wantBody    string
+		wantHeader  map[string]string
+	}{
+		{
+			name: "data",
+			handler: func(ctx *Context) {
+				ctx.Data(http.StatusCreated, "text/csv", []byte("a,b"))
+			},
+			wantCode:   http.StatusCreated,
+			wantBody:   "a,b",
+			wantHeader: map[string]string{"Content-Type": "text/csv"},
+		},
+		{
+			name: "data from reader",
+			handler: func(ctx *Context) {
+				ctx.DataFromReader(http.StatusOK, 5, "application/octet-stream", strings.NewReader("hello"),
+					map[string]string{"X-Source": "reader"})
+			},
+			wantWritten: true,
+			wantCode:    http.StatusOK,
+			wantBody:    "hello",
+			wantHeader: map[string]string{
+				"Content-Type":   "application/octet-stream",
+				"Content-Length": "5",
+				"X-Source":       "reader",
+			},
+		},
+		{
+			name: "data from reader unknown length",
+			handler: func(ctx *Context) {
+				ctx.DataFromReader(http.StatusOK, -1, "text/plain", strings.NewReader("hello"), nil)
+			},
+			wantWritten: true,
+			wantCode:    http.StatusOK,
+			wantBody:    "hello",
+			wantHeader:  map[string]string{"Content-Length": ""},
+		},
+		{
+			name: "data from reader error",
+			handler: func(ctx *Context) {
+				ctx.DataFromReader(http.StatusOK, 5, "text/plain", iotest.ErrReader(errors.New("boom")), nil)
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: internalError,
+			wantHeader: map[string]string{
+				"Content-Type":   "text/plain; charset=utf-8",
+				"Content-Length": "",
+			},
+		},
+		{
+			name: "file attachment",
+			handler: func(ctx *Context) {
+				ctx.FileAttachment(file, "年度报告.txt")
+			},
+			wantWritten: true,
+			wantCode:    http.StatusOK,
+			wantBody:    "report",
+			wantHeader: map[string]string{
+				"Content-Disposition": `attachment; filename="____.txt"; filename*=UTF-8''%E5%B9%B4%E5%BA%A6%E6%8A%A5%E5%91%8A.txt`,
+			},
+		},
+		{
+			name: "file from fs",
+			handler: func(ctx *Context) {
+				ctx.FileFromFS("docs/readme.txt", files)
+			},
+			wantWritten: true,
+			wantCode:    http.StatusOK,
+			wantBody:    "readme",
+			wantHeader:  map[string]string{"Content-Type": "text/plain; charset=utf-8"},
+		},
+		{
+			name: "file from fs not found",
+			handler: func(ctx *Context) {
+				ctx.FileFromFS("../report.txt", files)
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var written bool
+			server := New()
+			server.GET("/", func(ctx *Context) {
+				tt.handler(ctx)
+				written = ctx.Written()
+			})
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantWritten, written)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+			for key, val := range tt.wantHeader {
+				assert.Equal(t, val, response.Header().Get(key), key)
+			}
+		})
+	}
+
+	// 目录响应 404，不会根据请求路径重定向
+	t.Run("file from fs directory", func(t *testing.T) {
+		server := New()
+		server.GET("/download", func(ctx *Context) {
+			ctx.FileFromFS("docs", fstest.MapFS{
+				"docs/index.html": {Data: []byte("index")},
+			})
+		})
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/download", nil))
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Empty(t, response.Header().Get("Location"))
+		assert.Empty(t, response.Body.String())
+	})
+
+}
@@ -0,0 +1,227 @@
+package web
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrNoCookieKeys 未配置 HttpServer.CookieKeys
+	ErrNoCookieKeys = errors.New("web：未配置 Cookie 密钥")
+	// ErrInvalidCookie Cookie 签名校验或者解密失败，可能被篡改或者密钥已经移除
+	ErrInvalidCookie = errors.New("web：Cookie 校验失败")
+	// ErrCookieTooLarge 编码后的 Cookie 超过浏览器 4096 字节的限制
+	ErrCookieTooLarge = errors.New("web：Cookie 超出大小限制")
+)
+
+// maxCookieSize 浏览器允许的单个 Cookie 最大字节数
+const maxCookieSize = 4096
+
+// CookieOptions Cookie 的属性
+type CookieOptions struct {
+	Path   string
+	Domain string
+	// MaxAge 过期时间，单位为秒，0 表示会话 Cookie，小于 0 表示删除 Cookie
+	MaxAge   int
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+	// Partitioned 使用 CHIPS 分区存储第三方 Cookie，需要同时设置 Secure
+	Partitioned bool
+}
+
+// Cookie 获取请求中 name 对应的 Cookie 值，值使用 url.PathUnescape 解码
+// '+' 保持原样不会解码为空格，其它服务或者 JS 设置的值无法解码时（例如包含单独的 '%'）返回原始值
+func (c *Context) Cookie(name string) (string, error) {
+	cookie, err := c.Request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	value, err := url.PathUnescape(cookie.Value)
+	if err != nil {
+		return cookie.Value, nil
+	}
+	return value, nil
+}
+
+// SetCookie 使用 HttpServer.CookieOptions 设置 Cookie，值使用 url.PathEscape 编码
+// maxAge 为过期时间，单位为秒，0 表示会话 Cookie，小于 0 表示删除 Cookie
+func (c *Context) SetCookie(name, value string, maxAge int) {
+	opts := c.h.CookieOptions
+	opts.MaxAge = maxAge
+	c.SetCookieWithOptions(name, value, opts)
+}
+
+// SetCookieWithOptions 使用指定的属性设置 Cookie，值使用 url.PathEscape 编码
+func (c *Context) SetCookieWithOptions(name, value string, opts CookieOptions) {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    url.PathEscape(value),
+		Path:     opts.Path,
+		Domain:   opts.Domain,
+		MaxAge:   opts.MaxAge,
+		Secure:   opts.Secure,
+		HttpOnly: opts.HttpOnly,
+		SameSite: opts.SameSite,
+	}
+	v := cookie.String()
+	if v == "" {
+		return
+	}
+	// http.Cookie 在 Go 1.23 之前不支持 Partitioned 属性
+	if opts.Partitioned {
+		v += "; Partitioned"
+	}
+	c.Writer.Header().Add("Set-Cookie", v)
+}
+
+// DeleteCookie 删除 Cookie，Path 和 Domain 使用 HttpServer.CookieOptions
+func (c *Context) DeleteCookie(name string) {
+	c.SetCookie(name, "", -1)
+}
+
+// SignedCookie 获取使用 SetSignedCookie 设置的 Cookie，签名不正确时返回 ErrInvalidCookie
+func (c *Context) SignedCookie(name string) (string, error) {
+	value, err := c.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	if len(c.h.CookieKeys) == 0 {
+		return "", ErrNoCookieKeys
+	}
+	return verifyCookie(c.h.CookieKeys, name, value)
+}
+
+// SetSignedCookie 设置使用 HMAC-SHA256 签名的 Cookie，客户端可以读取内容但无法篡改
+func (c *Context) SetSignedCookie(name, value string, maxAge int) error {
+	if len(c.h.CookieKeys) == 0 {
+		return ErrNoCookieKeys
+	}
+	return c.setEncodedCookie(name, signCookie(c.h.CookieKeys[0], name, value), maxAge)
+}
+
+// EncryptedCookie 获取使用 SetEncryptedCookie 设置的 Cookie，解密失败时返回 ErrInvalidCookie
+func (c *Context) EncryptedCookie(name string) (string, error) {
+	value, err := c.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	if len(c.h.CookieKeys) == 0 {
+		return "", ErrNoCookieKeys
+	}
+	return decryptCookie(c.h.CookieKeys, name, value)
+}
+
+// SetEncryptedCookie 设置使用 AES-GCM 加密的 Cookie，客户端无法读取和篡改内容
+func (c *Context) SetEncryptedCookie(name, value string, maxAge int) error {
+	if len(c.h.CookieKeys) == 0 {
+		return ErrNoCookieKeys
+	}
+	encrypted, err := encryptCookie(c.h.CookieKeys[0], name, value)
+	if err != nil {
+		return err
+	}
+	return c.setEncodedCookie(name, encrypted, maxAge)
+}
+
+func (c *Context) setEncodedCookie(name, value string, maxAge int) error {
+	if len(name)+len(value) > maxCookieSize {
+		return ErrCookieTooLarge
+	}
+	c.SetCookie(name, value, maxAge)
+	return nil
+}
+
+// deriveKey 根据用途从密钥派生出 32 字节的子密钥，签名和加密使用不同的子密钥
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// cookieMAC 签名包含 Cookie 名称，防止将一个 Cookie 的值复制到另一个 Cookie 中
+func cookieMAC(key []byte, name, value string) []byte {
+	mac := hmac.New(sha256.New, deriveKey(key, "web-cookie-sign"))
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// signCookie 签名后的格式为 base64(value).base64(mac)
+func signCookie(key []byte, name, value string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(value)) + "." +
+		base64.RawURLEncoding.EncodeToString(cookieMAC(key, name, value))
+}
+
+func verifyCookie(keys [][]byte, name, signed string) (string, error) {
+	encodedValue, encodedMAC, ok := strings.Cut(signed, ".")
+	if !ok {
+		return "", ErrInvalidCookie
+	}
+	value, err := base64.RawURLEncoding.DecodeString(encodedValue)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	for _, key := range keys {
+		if hmac.Equal(mac, cookieMAC(key, name, string(value))) {
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+func newCookieAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveKey(key, "web-cookie-encrypt"))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptCookie 加密后的格式为 base64(nonce + ciphertext)，Cookie 名称作为附加数据
+func encryptCookie(key []byte, name, value string) (string, error) {
+	aead, err := newCookieAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), []byte(name))), nil
+}
+
+func decryptCookie(keys [][]byte, name, encrypted string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	for _, key := range keys {
+		aead, err := newCookieAEAD(key)
+		if err != nil {
+			return "", err
+		}
+		if len(data) < aead.NonceSize() {
+			return "", ErrInvalidCookie
+		}
+		nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+		if value, err := aead.Open(nil, nonce, ciphertext, []byte(name)); err == nil {
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
@@ -0,0 +1,167 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_SetCookie(t *testing.T) {
+	server := New()
+	server.GET("/set", func(ctx *Context) {
+		ctx.SetCookie("user", "ray chen", 3600)
+		ctx.SetCookieWithOptions("embed", "1", CookieOptions{
+			Path:        "/widget",
+			Secure:      true,
+			SameSite:    http.SameSiteNoneMode,
+			Partitioned: true,
+		})
+		ctx.DeleteCookie("token")
+	})
+	server.GET("/get", func(ctx *Context) {
+		user, err := ctx.Cookie("user")
+		require.NoError(t, err)
+		_, err = ctx.Cookie("missing")
+		assert.Equal(t, http.ErrNoCookie, err)
+		ctx.String(http.StatusOK, user)
+	})
+
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/set", nil))
+	assert.Equal(t, []string{
+		"user=ray%20chen; Path=/; Max-Age=3600; HttpOnly; SameSite=Lax",
+		"embed=1; Path=/widget; Secure; SameSite=None; Partitioned",
+		"token=; Path=/; Max-Age=0; HttpOnly; SameSite=Lax",
+	}, response.Header().Values("Set-Cookie"))
+
+	testCases := []struct {
+		name     string
+		cookie   string
+		wantUser string
+	}{
+		{
+			name:     "set by SetCookie",
+			cookie:   "user=ray%20chen",
+			wantUser: "ray chen",
+		},
+		{
+			// 其它服务或者 JS 设置的 Cookie
+			name:     "plus sign",
+			cookie:   "user=ray+chen",
+			wantUser: "ray+chen",
+		},
+		{
+			name:     "stray percent",
+			cookie:   "user=100%",
+			wantUser: "100%",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/get", nil)
+			request.Header.Set("Cookie", tt.cookie)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantUser, response.Body.String())
+		})
+	}
+}
+
+func TestContext_SecureCookie(t *testing.T) {
+	oldKey, newKey := []byte("old-secret-key"), []byte("new-secret-key")
+
+	testCases := []struct {
+		name    string
+		set     func(ctx *Context, name, value string) error
+		get     func(ctx *Context, name string) (string, error)
+		visible bool
+	}{
+		{
+			name: "signed",
+			set: func(ctx *Context, name, value string) error {
+				return ctx.SetSignedCookie(name, value, 0)
+			},
+			get: func(ctx *Context, name string) (string, error) {
+				return ctx.SignedCookie(name)
+			},
+			visible: true,
+		},
+		{
+			name: "encrypted",
+			set: func(ctx *Context, name, value string) error {
+				return ctx.SetEncryptedCookie(name, value, 0)
+			},
+			get: func(ctx *Context, name string) (string, error) {
+				return ctx.EncryptedCookie(name)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := New()
+			server.CookieKeys = [][]byte{oldKey}
+			server.GET("/set", func(ctx *Context) {
+				require.NoError(t, tt.set(ctx, "role", "admin"))
+				assert.Equal(t, ErrCookieTooLarge, tt.set(ctx, "big", strings.Repeat("a", maxCookieSize)))
+			})
+			server.GET("/get", func(ctx *Context) {
+				value, err := tt.get(ctx, ctx.Query("name"))
+				if err != nil {
+					ctx.String(http.StatusUnauthorized, err.Error())
+					return
+				}
+				ctx.String(http.StatusOK, value)
+			})
+			get := func(name, cookie string) (int, string) {
+				request := httptest.NewRequest(http.MethodGet, "/get?name="+name, nil)
+				request.Header.Set("Cookie", cookie)
+				response := httptest.NewRecorder()
+				server.ServeHTTP(response, request)
+				return response.Code, response.Body.String()
+			}
+
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/set", nil))
+			cookies := response.Result().Cookies()
+			require.Len(t, cookies, 1)
+			value := cookies[0].Value
+			assert.Equal(t, tt.visible, strings.Contains(value, "YWRtaW4"), value)
+
+			code, body := get("role", "role="+value)
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, "admin", body)
+
+			// 篡改 Cookie
+			tampered := []byte(value)
+			tampered[0] ^= 1
+			code, body = get("role", "role="+string(tampered))
+			assert.Equal(t, http.StatusUnauthorized, code)
+			assert.Equal(t, ErrInvalidCookie.Error(), body)
+
+			// 复制到其它 Cookie
+			code, _ = get("admin", "admin="+value)
+			assert.Equal(t, http.StatusUnauthorized, code)
+
+			// 密钥轮换后旧 Cookie 仍然有效，新 Cookie 使用新密钥
+			server.CookieKeys = [][]byte{newKey, oldKey}
+			code, body = get("role", "role="+value)
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, "admin", body)
+
+			// 移除旧密钥后旧 Cookie 失效
+			server.CookieKeys = [][]byte{newKey}
+			code, _ = get("role", "role="+value)
+			assert.Equal(t, http.StatusUnauthorized, code)
+
+			server.CookieKeys = nil
+			code, body = get("role", "role="+value)
+			assert.Equal(t, http.StatusUnauthorized, code)
+			assert.Equal(t, ErrNoCookieKeys.Error(), body)
+		})
+	}
+}
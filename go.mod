@@ -2,12 +2,16 @@ module github.com/killlowkey/web
 
 go 1.19
 
-require github.com/stretchr/testify v1.8.1
+require (
+	github.com/patrickmn/go-cache v2.1.0+incompatible
+	github.com/pelletier/go-toml/v2 v2.0.6
+	github.com/stretchr/testify v1.8.1
+	github.com/ugorji/go/codec v1.2.11
+	google.golang.org/protobuf v1.29.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	google.golang.org/protobuf v1.29.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
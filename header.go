@@ -0,0 +1,67 @@
+package web
+
+import (
+	"net/http"
+)
+
+// GetHeader 获取请求头 key 的值
+func (c *Context) GetHeader(key string) string {
+	return c.Request.Header.Get(key)
+}
+
+// SetHeader 设置响应头，替换已有的值，val 为空时删除响应头
+func (c *Context) SetHeader(key, val string) {
+	if val == "" {
+		c.Writer.Header().Del(key)
+		return
+	}
+	c.Writer.Header().Set(key, val)
+}
+
+// AddHeader 添加响应头，保留已有的值，例如多个 Link、Vary
+func (c *Context) AddHeader(key, val string) {
+	c.Writer.Header().Add(key, val)
+}
+
+// SetTrailer 设置在响应 body 之后写回的 trailer，例如 body 的校验和
+// 响应开始写入前设置时会在 Trailer 响应头中声明，客户端可以提前知道 trailer 的名称
+func (c *Context) SetTrailer(key, val string) {
+	key = http.CanonicalHeaderKey(key)
+	if c.trailers == nil {
+		c.trailers = make(http.Header)
+	}
+	if _, declared := c.trailers[key]; !declared && !c.written {
+		c.Writer.Header().Add("Trailer", key)
+	}
+	c.trailers.Set(key, val)
+}
+
+// BeforeWrite 注册写回状态码之前执行的回调，与 defer 相同，后注册的先执行
+// 外层 Middleware 注册的回调最后执行，可以看到处理器和内层回调修改后的响应
+// 回调中仍然可以修改响应头和 Context.RespStatus，例如添加统一的响应头、记录处理耗时
+func (c *Context) BeforeWrite(fn func(ctx *Context)) {
+	if fn == nil {
+		panic("web：BeforeWrite 传入回调为 nil")
+	}
+	c.beforeWrite = append(c.beforeWrite, fn)
+}
+
+// runBeforeWrite 执行 BeforeWrite 注册的回调，只会执行一次
+func (c *Context) runBeforeWrite() {
+	// 回调中可能继续注册回调，同样需要执行
+	for len(c.beforeWrite) > 0 {
+		hooks := c.beforeWrite
+		c.beforeWrite = nil
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hooks[i](c)
+		}
+	}
+}
+
+// writeTrailers 写回 trailer，使用 http.TrailerPrefix 支持响应开始写入后才设置的 trailer
+func (c *Context) writeTrailers() {
+	header := c.Writer.Header()
+	for key, values := range c.trailers {
+		header[http.TrailerPrefix+key] = values
+	}
+}
@@ -0,0 +1,138 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_Header(t *testing.T) {
+	server := New()
+	server.Use(func(next HandleFunc) HandleFunc {
+		return func(ctx *Context) {
+			ctx.BeforeWrite(func(ctx *Context) {
+				ctx.SetHeader("X-Status", http.StatusText(ctx.RespStatus))
+			})
+			next(ctx)
+		}
+	})
+	server.GET("/header", func(ctx *Context) {
+		ctx.SetHeader("Content-Type", "text/plain")
+		ctx.SetHeader("Content-Type", "application/json")
+		ctx.AddHeader("Vary", "Accept")
+		ctx.AddHeader("Vary", "Accept-Encoding")
+		ctx.SetHeader("X-Tenant", ctx.GetHeader("X-Tenant"))
+		ctx.Write([]byte(`{}`))
+	})
+	server.GET("/hook", func(ctx *Context) {
+		ctx.BeforeWrite(func(ctx *Context) {
+			ctx.Status(http.StatusAccepted)
+		})
+		ctx.String(http.StatusOK, "hook")
+	})
+	server.GET("/stream", func(ctx *Context) {
+		ctx.DataFromReader(http.StatusCreated, 6, "text/plain", strings.NewReader("stream"), nil)
+	})
+
+	testCases := []struct {
+		name       string
+		path       string
+		header     http.Header
+		wantCode   int
+		wantHeader http.Header
+	}{
+		{
+			name:     "set and add header",
+			path:     "/header",
+			header:   http.Header{"X-Tenant": {"ray"}},
+			wantCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type": {"application/json"},
+				"Vary":         {"Accept", "Accept-Encoding"},
+				"X-Tenant":     {"ray"},
+				"X-Status":     {"OK"},
+			},
+		},
+		{
+			name:       "hook change status",
+			path:       "/hook",
+			wantCode:   http.StatusAccepted,
+			wantHeader: http.Header{"X-Status": {"Accepted"}},
+		},
+		{
+			name:       "hook before stream",
+			path:       "/stream",
+			wantCode:   http.StatusCreated,
+			wantHeader: http.Header{"X-Status": {"Created"}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.header {
+				request.Header[k] = v
+			}
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			for k, v := range tt.wantHeader {
+				assert.Equal(t, v, response.Header().Values(k))
+			}
+		})
+	}
+}
+
+func TestContext_SetTrailer(t *testing.T) {
+	server := New()
+	server.GET("/buffered", func(ctx *Context) {
+		ctx.SetTrailer("X-Checksum", "abc")
+		ctx.String(http.StatusOK, "buffered")
+	})
+	server.GET("/stream", func(ctx *Context) {
+		ctx.SetTrailer("x-checksum", "abc")
+		ctx.DataFromReader(http.StatusOK, -1, "text/plain", strings.NewReader("stream"), nil)
+		// 响应开始写入后设置的 trailer 没有声明
+		ctx.SetTrailer("X-Rows", "1")
+	})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	testCases := []struct {
+		name        string
+		path        string
+		wantBody    string
+		wantTrailer http.Header
+	}{
+		{
+			name:        "buffered",
+			path:        "/buffered",
+			wantBody:    "buffered",
+			wantTrailer: http.Header{"X-Checksum": {"abc"}},
+		},
+		{
+			name:        "stream",
+			path:        "/stream",
+			wantBody:    "stream",
+			wantTrailer: http.Header{"X-Checksum": {"abc"}, "X-Rows": {"1"}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(ts.URL + tt.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			// 声明的 trailer 在读取 body 之前就可以获取名称
+			assert.Contains(t, resp.Trailer, "X-Checksum")
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBody, string(body))
+			assert.Equal(t, tt.wantTrailer, resp.Trailer)
+		})
+	}
+}
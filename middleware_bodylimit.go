@@ -0,0 +1,62 @@
+package web
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+const entityTooLarge = "413 request entity too large"
+
+// BodyLimit 限制请求 body 最多读取 n 个字节，读取超出限制时响应 413
+// 可以用于 RouterGroup，覆盖 HttpServer.MaxBodyBytes 全局配置
+func BodyLimit(n int64) Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(ctx *Context) {
+			ctx.limitBody(n)
+			defer func() {
+				if body, ok := ctx.Request.Body.(*limitedBody); ok && body.exceeded.Load() && !ctx.Written() {
+					ctx.Writer.Header().Del("Content-Type")
+					// 剩余的 body 没有读取，回写响应后关闭连接
+					ctx.Writer.Header().Set("Connection", "close")
+					ctx.String(http.StatusRequestEntityTooLarge, entityTooLarge)
+				}
+			}()
+			next(ctx)
+		}
+	}
+}
+
+// limitedBody 记录读取请求 body 时是否超出限制
+// 超时的处理器可能在其它 goroutine 中继续读取 body，exceeded 需要并发安全
+type limitedBody struct {
+	io.ReadCloser
+	raw      io.ReadCloser // 未限制的原始 body
+	exceeded atomic.Bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded.Store(true)
+	}
+	return n, err
+}
+
+// limitBody 限制请求 body 的大小，多次调用时以最后一次为准
+func (c *Context) limitBody(n int64) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return
+	}
+	raw := c.Request.Body
+	if body, ok := raw.(*limitedBody); ok {
+		raw = body.raw
+	}
+	// 不传入 Writer，http.MaxBytesReader 不会在 ServeHTTP 返回之后回调 http.ResponseWriter
+	c.Request.Body = &limitedBody{
+		ReadCloser: http.MaxBytesReader(nil, raw, n),
+		raw:        raw,
+	}
+}
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyLimit(t *testing.T) {
+	server := New()
+	server.MaxBodyBytes = 16
+	handler := func(ctx *Context) {
+		user := &User{}
+		if err := ctx.BindJSON(user); err != nil {
+			ctx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx.JSON(http.StatusOK, user)
+	}
+	server.POST("/user", handler)
+	server.Group("/upload", BodyLimit(64)).POST("/user", handler)
+
+	testCases := []struct {
+		name     string
+		path     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "within global limit",
+			path:     "/user",
+			body:     `{"Name":"ray"}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"Name":"ray","Age":0}`,
+		},
+		{
+			name:     "exceed global limit",
+			path:     "/user",
+			body:     `{"Name":"ray","Age":18}`,
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantBody: entityTooLarge,
+		},
+		{
+			name:     "group limit",
+			path:     "/upload/user",
+			body:     `{"Name":"ray","Age":18}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"Name":"ray","Age":18}`,
+		},
+		{
+			name:     "exceed group limit",
+			path:     "/upload/user",
+			body:     `{"Name":"` + strings.Repeat("a", 64) + `"}`,
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantBody: entityTooLarge,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+		})
+	}
+}
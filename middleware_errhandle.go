@@ -3,8 +3,11 @@ package web
 import "net/http"
 
 const (
-	notFound   = "404 not found"
-	notAllowed = "405 not allowed"
+	invalidCallback = "400 invalid jsonp callback"
+	unsafeRedirect  = "400 unsafe redirect"
+	notFound        = "404 not found"
+	notAllowed      = "405 not allowed"
+	internalError   = "500 internal server error"
 )
 
 type ErrHandleMiddleWareBuild struct {
@@ -25,6 +28,9 @@ func (e *ErrHandleMiddleWareBuild) Build() Middleware {
 	return func(next HandleFunc) HandleFunc {
 		return func(ctx *Context) {
 			defer func() {
+				if ctx.Written() {
+					return
+				}
 				if handler, ok := e.handlers[ctx.RespStatus]; ok {
 					handler(ctx)
 				}
@@ -9,8 +9,10 @@ import (
 type LogEntry struct {
 	Method     string        // 请求方法
 	Path       string        // 请求路径
+	ClientIP   string        // 客户端 IP
 	RespStatus int           // 响应状态码
 	Latency    time.Duration // 处理耗时
+	Errors     []error       // 处理请求过程中记录的错误，例如渲染失败，见 Context.Error
 }
 
 type LogWriter interface {
@@ -38,9 +40,14 @@ func (b *LogMiddlewareBuild) Build() Middleware {
 				e := &LogEntry{
 					Method:     ctx.Request.Method,
 					Path:       ctx.Request.URL.Path,
+					ClientIP:   ctx.ClientIP(),
 					Latency:    time.Now().Sub(start),
 					RespStatus: ctx.RespStatus,
 				}
+				// Context 会被复用，需要复制一份
+				if len(ctx.Errors) > 0 {
+					e.Errors = append([]error(nil), ctx.Errors...)
+				}
 				err := b.writer.Write(e)
 				if err != nil {
 					log.Println("web：[Log Middleware]写入 log 出错：" + err.Error())
@@ -55,9 +62,12 @@ type ConsoleLogger struct {
 }
 
 func (c ConsoleLogger) Write(e *LogEntry) error {
-	msg := fmt.Sprintf("[web]: %v | %#v %s %d %v",
+	msg := fmt.Sprintf("[web]: %v | %s | %#v %s %d %v",
 		time.Now().Format("2006/01/02 - 15:04:05"),
-		e.Path, e.Method, e.RespStatus, e.Latency)
+		e.ClientIP, e.Path, e.Method, e.RespStatus, e.Latency)
+	for _, err := range e.Errors {
+		msg += "\n\terror: " + err.Error()
+	}
 	fmt.Println(msg)
 	return nil
 }
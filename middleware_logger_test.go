@@ -16,6 +16,9 @@ type LoggerTest struct {
 
 func (c LoggerTest) Write(l *LogEntry) error {
 	msg := fmt.Sprintf("%s-%s-%d", l.Method, l.Path, l.RespStatus)
+	for _, err := range l.Errors {
+		msg += "-" + err.Error()
+	}
 	_, _ = c.body.Write([]byte(msg))
 	return nil
 }
@@ -33,6 +36,9 @@ func TestAccesslog(t *testing.T) {
 	server.GET("/api/user", func(ctx *Context) {
 		ctx.String(200, "hello")
 	})
+	server.GET("/render", func(ctx *Context) {
+		ctx.JSON(http.StatusOK, make(chan int))
+	})
 
 	testCases := []struct {
 		name    string
@@ -49,6 +55,11 @@ func TestAccesslog(t *testing.T) {
 			path:    "/api/user",
 			wantRes: "GET-/api/user-200",
 		},
+		{
+			name:    "render error",
+			path:    "/render",
+			wantRes: "GET-/render-500-json: unsupported type: chan int",
+		},
 	}
 
 	for _, tt := range testCases {
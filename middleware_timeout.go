@@ -0,0 +1,178 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const serviceUnavailable = "503 service unavailable"
+
+type TimeoutMiddlewareBuild struct {
+	timeout time.Duration
+	status  int
+	body    string
+	handler HandleFunc
+}
+
+// NewTimeoutMiddlewareBuild 处理器超过 timeout 未返回时响应超时，默认响应 503
+func NewTimeoutMiddlewareBuild(timeout time.Duration) *TimeoutMiddlewareBuild {
+	if timeout <= 0 {
+		panic("web：timeout middleware 超时时间必须大于 0")
+	}
+	return &TimeoutMiddlewareBuild{
+		timeout: timeout,
+		status:  http.StatusServiceUnavailable,
+		body:    serviceUnavailable,
+	}
+}
+
+// Status 设置超时响应的状态码，例如 http.StatusGatewayTimeout
+func (m *TimeoutMiddlewareBuild) Status(status int) *TimeoutMiddlewareBuild {
+	m.status = status
+	return m
+}
+
+// Body 设置超时响应的内容
+func (m *TimeoutMiddlewareBuild) Body(body string) *TimeoutMiddlewareBuild {
+	m.body = body
+	return m
+}
+
+// Handler 使用 handler 生成超时响应，设置后 Status 和 Body 不再生效
+func (m *TimeoutMiddlewareBuild) Handler(handler HandleFunc) *TimeoutMiddlewareBuild {
+	m.handler = handler
+	return m
+}
+
+// Build 处理器在单独的 goroutine 中执行，使用 Context 的副本并缓存响应
+// 在超时前返回时将响应、值和错误合并回 Context，超时后处理器的写入不会影响响应
+// 处理器应该监听 Context.Done，超时后及时返回
+func (m *TimeoutMiddlewareBuild) Build() Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(ctx *Context) {
+			timeoutCtx, cancel := context.WithTimeout(ctx.Request.Context(), m.timeout)
+			defer cancel()
+
+			writer := &timeoutWriter{header: make(http.Header)}
+			for k, v := range ctx.Writer.Header() {
+				writer.header[k] = append([]string(nil), v...)
+			}
+			tc := ctx.Copy()
+			tc.Request = ctx.Request.WithContext(timeoutCtx)
+			tc.Writer = writer
+			tc.RespData = append([]byte(nil), ctx.RespData...)
+			tc.Errors = append([]error(nil), ctx.Errors...)
+			tc.buffered = ctx.buffered
+			tc.forwards = ctx.forwards
+			tc.trailers = ctx.trailers.Clone()
+			// BeforeWrite 回调不复制到副本，超时后处理器继续写入时不会并发执行外层注册的回调
+			// 未超时时合并回 Context，由 flush 执行
+
+			done := make(chan struct{})
+			panicChan := make(chan any, 1)
+			go func() {
+				defer func() {
+					if err := recover(); err != nil {
+						panicChan <- err
+					}
+				}()
+				next(tc)
+				close(done)
+			}()
+
+			select {
+			case err := <-panicChan:
+				// 在当前 goroutine 中重新 panic，交给 recovery middleware 处理
+				panic(err)
+			case <-done:
+				writer.mu.Lock()
+				defer writer.mu.Unlock()
+				ctx.merge(tc, writer)
+			case <-timeoutCtx.Done():
+				writer.mu.Lock()
+				writer.timedOut = true
+				writer.mu.Unlock()
+
+				ctx.Error(fmt.Errorf("web：处理请求超时 %s %w", m.timeout, http.ErrHandlerTimeout))
+				if m.handler != nil {
+					m.handler(ctx)
+					return
+				}
+				ctx.Writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				ctx.String(m.status, m.body)
+			}
+		}
+	}
+}
+
+// Timeout 使用默认配置创建超时 Middleware，可以用于 RouterGroup 或者单个路由
+// 例如 server.GET("/report", Timeout(time.Second)(handler))
+func Timeout(timeout time.Duration) Middleware {
+	return NewTimeoutMiddlewareBuild(timeout).Build()
+}
+
+// merge 合并在超时前完成的副本 tc，直接写入 writer 的响应转换为缓存的响应
+func (c *Context) merge(tc *Context, writer *timeoutWriter) {
+	header := c.Writer.Header()
+	for k := range header {
+		delete(header, k)
+	}
+	for k, v := range writer.header {
+		header[k] = v
+	}
+
+	if tc.written {
+		c.RespStatus, c.RespData = writer.status, writer.data
+	} else {
+		c.RespStatus, c.RespData = tc.RespStatus, tc.RespData
+	}
+	c.Params, c.Route, c.handler = tc.Params, tc.Route, tc.handler
+	c.Errors, c.forwards = tc.Errors, tc.forwards
+	c.trailers = tc.trailers
+	c.beforeWrite = append(c.beforeWrite, tc.beforeWrite...)
+
+	tc.mu.RLock()
+	values := tc.UserValues
+	tc.mu.RUnlock()
+	c.mu.Lock()
+	c.UserValues = values
+	c.mu.Unlock()
+}
+
+// timeoutWriter 缓存处理器直接写入的响应，超时后拒绝写入
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	status   int
+	data     []byte
+	timedOut bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.data = append(w.data, data...)
+	return len(data), nil
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut || w.status != 0 {
+		return
+	}
+	w.status = code
+}
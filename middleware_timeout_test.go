@@ -0,0 +1,255 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	server := New()
+	late := make(chan struct{})
+	slow := func(ctx *Context) {
+		<-ctx.Done()
+		ctx.String(http.StatusOK, "late")
+	}
+
+	server.GET("/fast", Timeout(time.Second)(func(ctx *Context) {
+		ctx.Header("X-Handler", "fast")
+		ctx.Set("user", "ray")
+		ctx.String(http.StatusOK, "fast")
+	}))
+	server.GET("/slow", Timeout(10*time.Millisecond)(slow))
+	server.GET("/late", Timeout(10*time.Millisecond)(func(ctx *Context) {
+		<-ctx.Done()
+		defer close(late)
+		ctx.Header("X-Handler", "late")
+		ctx.DataFromReader(http.StatusOK, 4, "text/plain", strings.NewReader("late"), nil)
+		ctx.String(http.StatusOK, "late")
+	}))
+	server.GET("/panic", Timeout(time.Second)(func(ctx *Context) {
+		panic("boom")
+	}))
+	group := server.Group("/api", NewTimeoutMiddlewareBuild(10*time.Millisecond).
+		Status(http.StatusGatewayTimeout).Body("504 gateway timeout").Build())
+	group.GET("/slow", slow)
+	custom := server.Group("/custom", NewTimeoutMiddlewareBuild(10*time.Millisecond).
+		Handler(func(ctx *Context) {
+			ctx.JSON(http.StatusServiceUnavailable, H{"msg": "timeout"})
+		}).Build())
+	custom.GET("/slow", slow)
+
+	testCases := []struct {
+		name       string
+		path       string
+		wantCode   int
+		wantBody   string
+		wantHeader string
+	}{
+		{
+			name:       "within deadline",
+			path:       "/fast",
+			wantCode:   http.StatusOK,
+			wantBody:   "fast",
+			wantHeader: "fast",
+		},
+		{
+			name:     "route timeout",
+			path:     "/slow",
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: serviceUnavailable,
+		},
+		{
+			name:     "late write",
+			path:     "/late",
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: serviceUnavailable,
+		},
+		{
+			name:     "group timeout",
+			path:     "/api/slow",
+			wantCode: http.StatusGatewayTimeout,
+			wantBody: "504 gateway timeout",
+		},
+		{
+			name:     "custom handler",
+			path:     "/custom/slow",
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: `{"msg":"timeout"}`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			if tt.path == "/late" {
+				<-late
+			}
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+			assert.Equal(t, tt.wantHeader, response.Header().Get("X-Handler"))
+		})
+	}
+
+	t.Run("panic", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		assert.PanicsWithValue(t, "boom", func() {
+			server.ServeHTTP(httptest.NewRecorder(), request)
+		})
+	})
+}
+
+func TestHttpServer_RequestTimeout(t *testing.T) {
+	server := New()
+	server.RequestTimeout = 10 * time.Millisecond
+	var user string
+	server.Use(func(next HandleFunc) HandleFunc {
+		return func(ctx *Context) {
+			next(ctx)
+			user = ctx.GetString("user")
+		}
+	})
+	server.GET("/user", func(ctx *Context) {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		ctx.Set("user", "ray")
+		ctx.String(http.StatusOK, "ray")
+	})
+	server.GET("/slow", func(ctx *Context) {
+		<-ctx.Done()
+	})
+
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/user", nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "ray", response.Body.String())
+	assert.Equal(t, "ray", user)
+
+	response = httptest.NewRecorder()
+	server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, serviceUnavailable, response.Body.String())
+}
+
+func TestTimeout_BodyLimit(t *testing.T) {
+	server := New()
+	server.MaxBodyBytes = 16
+	server.RequestTimeout = 10 * time.Millisecond
+	var wg sync.WaitGroup
+	// 超时之后继续读取 body，与 BodyLimit 的检查并发执行
+	late := func(ctx *Context) {
+		defer wg.Done()
+		<-ctx.Done()
+		_, _ = io.ReadAll(ctx.Request.Body)
+	}
+	server.POST("/late", late)
+	server.POST("/read", func(ctx *Context) {
+		defer wg.Done()
+		if _, err := io.ReadAll(ctx.Request.Body); err != nil {
+			return
+		}
+		ctx.String(http.StatusOK, "ok")
+	})
+	server.Group("/group", BodyLimit(16)).POST("/late", Timeout(10*time.Millisecond)(late))
+
+	testCases := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "exceed within deadline",
+			path:     "/read",
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantBody: entityTooLarge,
+		},
+		{
+			name:     "read after timeout",
+			path:     "/late",
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: serviceUnavailable,
+		},
+		{
+			name:     "group limit and route timeout",
+			path:     "/group/late",
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: serviceUnavailable,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			wg.Add(1)
+			request := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(strings.Repeat("a", 64)))
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			wg.Wait()
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+		})
+	}
+}
+
+func TestTimeout_BeforeWrite(t *testing.T) {
+	server := New()
+	var wg sync.WaitGroup
+	server.Use(func(next HandleFunc) HandleFunc {
+		return func(ctx *Context) {
+			// 回调使用外层的 Context，不能在超时的处理器中执行
+			ctx.BeforeWrite(func(*Context) {
+				ctx.AddHeader("X-Hook", "outer")
+			})
+			next(ctx)
+		}
+	})
+	handler := func(ctx *Context) {
+		defer wg.Done()
+		ctx.BeforeWrite(func(ctx *Context) {
+			ctx.AddHeader("X-Hook", "inner")
+		})
+		if ctx.Query("slow") != "" {
+			<-ctx.Done()
+		}
+		ctx.DataFromReader(http.StatusOK, 2, "text/plain", strings.NewReader("ok"), nil)
+	}
+	server.GET("/", Timeout(10*time.Millisecond)(handler))
+
+	testCases := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantHook []string
+	}{
+		{
+			name:     "stream within deadline",
+			path:     "/",
+			wantCode: http.StatusOK,
+			wantHook: []string{"inner", "outer"},
+		},
+		{
+			name:     "stream after timeout",
+			path:     "/?slow=1",
+			wantCode: http.StatusServiceUnavailable,
+			wantHook: []string{"outer"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			wg.Add(1)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			wg.Wait()
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantHook, response.Header().Values("X-Hook"))
+		})
+	}
+}
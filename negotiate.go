@@ -0,0 +1,184 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// 内容协商支持的 MIME 类型
+const (
+	MIMEJSON     = "application/json"
+	MIMEXML      = "application/xml"
+	MIMEXML2     = "text/xml"
+	MIMEHTML     = "text/html"
+	MIMEPlain    = "text/plain"
+	MIMEYAML     = "application/x-yaml"
+	MIMEYAML2    = "application/yaml"
+	MIMEPROTOBUF = "application/x-protobuf"
+)
+
+const notAcceptable = "406 not acceptable"
+
+// Negotiate 内容协商配置，Offered 为服务端可以提供的 MIME 类型，按照优先级排列
+// 各类型的数据未设置时使用 Data
+type Negotiate struct {
+	Offered   []string
+	HTMLName  string
+	HTMLData  any
+	JSONData  any
+	XMLData   any
+	YAMLData  any
+	ProtoData any
+	Data      any
+}
+
+// Negotiate 根据请求的 Accept 选择响应格式，没有可接受的格式时响应 406
+// 响应的 Content-Type 总是协商选出的类型，覆盖处理器之前设置的 Content-Type
+func (c *Context) Negotiate(code int, config Negotiate) {
+	c.addVary("Accept")
+
+	switch format := c.NegotiateFormat(config.Offered...); format {
+	case MIMEJSON:
+		c.setContentType(format)
+		c.JSON(code, chooseData(config.JSONData, config.Data))
+	case MIMEXML, MIMEXML2:
+		c.setContentType(format)
+		c.XML(code, chooseData(config.XMLData, config.Data))
+	case MIMEHTML:
+		c.setContentType(format)
+		c.HTML(code, config.HTMLName, chooseData(config.HTMLData, config.Data))
+	case MIMEYAML, MIMEYAML2:
+		c.setContentType(format)
+		c.YAML(code, chooseData(config.YAMLData, config.Data))
+	case MIMEPROTOBUF:
+		c.setContentType(format)
+		c.ProtoBuf(code, chooseData(config.ProtoData, config.Data))
+	case "":
+		c.setContentType(MIMEPlain)
+		c.String(http.StatusNotAcceptable, notAcceptable)
+	default:
+		c.Error(fmt.Errorf("web：Negotiate 不支持的格式 %s", format))
+		c.setContentType(MIMEPlain)
+		c.WriteWithStatus(http.StatusInternalServerError, []byte(internalError))
+	}
+}
+
+// setContentType 设置 Content-Type，文本类型添加 charset，protobuf 为二进制数据不添加
+func (c *Context) setContentType(mimeType string) {
+	if mimeType != MIMEPROTOBUF {
+		mimeType += "; charset=utf-8"
+	}
+	c.Writer.Header().Set("Content-Type", mimeType)
+}
+
+// NegotiateFormat 根据请求的 Accept 从 offered 中选出最合适的 MIME 类型
+// 请求未携带 Accept 时返回 offered 的第一个，没有可接受的类型时返回空字符串
+// 质量值相同时按照 offered 中的顺序优先
+func (c *Context) NegotiateFormat(offered ...string) string {
+	if len(offered) == 0 {
+		return ""
+	}
+	accept := c.Request.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return offered[0]
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQ := "", 0.0
+	for _, offer := range offered {
+		if q := acceptQuality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// addVary 向 Vary 响应头追加字段，已存在时忽略
+func (c *Context) addVary(field string) {
+	header := c.Writer.Header()
+	for _, v := range header.Values("Vary") {
+		for _, f := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(f), field) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", field)
+}
+
+func chooseData(custom, wildcard any) any {
+	if custom != nil {
+		return custom
+	}
+	return wildcard
+}
+
+// acceptRange Accept 中的一项，例如 text/html;q=0.8
+type acceptRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// parseAccept 解析 Accept 请求头，忽略格式错误的项
+func parseAccept(accept string) []acceptRange {
+	ranges := make([]acceptRange, 0, 4)
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		typ, subtype, ok := splitMediaType(params[0])
+		if !ok {
+			continue
+		}
+		r := acceptRange{typ: typ, subtype: subtype, q: 1}
+		for _, param := range params[1:] {
+			key, val, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || !strings.EqualFold(strings.TrimSpace(key), "q") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+			if err != nil || q < 0 || q > 1 {
+				q = 0
+			}
+			r.q = q
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// acceptQuality 计算 offer 的质量值，由匹配的最具体的一项决定
+// 例如 text/*;q=0.5, text/html;q=0 时 text/html 不可接受
+func acceptQuality(ranges []acceptRange, offer string) float64 {
+	typ, subtype, ok := splitMediaType(strings.Split(offer, ";")[0])
+	if !ok {
+		return 0
+	}
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		var s int
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*" && r.subtype == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
+
+func splitMediaType(mediaType string) (string, string, bool) {
+	typ, subtype, found := strings.Cut(strings.ToLower(strings.TrimSpace(mediaType)), "/")
+	if !found || typ == "" || subtype == "" {
+		return "", "", false
+	}
+	return typ, subtype, true
+}
@@ -0,0 +1,173 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_NegotiateFormat(t *testing.T) {
+	offered := []string{MIMEJSON, MIMEXML, MIMEHTML}
+	testCases := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{name: "no accept", accept: "", want: MIMEJSON},
+		{name: "exact", accept: "application/xml", want: MIMEXML},
+		{name: "wildcard", accept: "*/*", want: MIMEJSON},
+		{name: "subtype wildcard", accept: "text/*", want: MIMEHTML},
+		{name: "q value", accept: "application/json;q=0.5, text/html", want: MIMEHTML},
+		{name: "q zero excludes", accept: "*/*, application/json;q=0", want: MIMEXML},
+		{name: "specific overrides wildcard", accept: "text/*;q=0.9, text/html;q=0, application/xml;q=0.1", want: MIMEXML},
+		{name: "tie keeps offered order", accept: "text/html, application/xml", want: MIMEXML},
+		{name: "case insensitive", accept: "Application/XML", want: MIMEXML},
+		{name: "not acceptable", accept: "image/png", want: ""},
+		{name: "malformed", accept: "json, ;q=1", want: ""},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, "/", nil)
+			assert.NoError(t, err)
+			request.Header.Set("Accept", tt.accept)
+			ctx := &Context{Request: request}
+			assert.Equal(t, tt.want, ctx.NegotiateFormat(offered...))
+		})
+	}
+}
+
+func TestContext_Negotiate(t *testing.T) {
+	server := New()
+	server.GET("/user", func(ctx *Context) {
+		ctx.Negotiate(http.StatusOK, Negotiate{
+			Offered:  []string{MIMEJSON, MIMEXML, MIMEXML2},
+			JSONData: H{"name": "ray"},
+			Data:     &User{Name: "ray", Age: 18},
+		})
+	})
+	server.GET("/config", func(ctx *Context) {
+		ctx.Negotiate(http.StatusOK, Negotiate{
+			Offered:  []string{MIMEJSON, MIMEYAML, MIMEYAML2},
+			YAMLData: H{"name": "ray"},
+			Data:     H{"name": "tom"},
+		})
+	})
+	server.GET("/preset", func(ctx *Context) {
+		// 处理器设置的 Content-Type 被协商的类型覆盖
+		ctx.Header("Content-Type", "application/vnd.api+json")
+		ctx.Negotiate(http.StatusOK, Negotiate{
+			Offered: []string{MIMEJSON, MIMEXML, MIMEYAML},
+			Data:    H{"name": "ray"},
+			XMLData: &User{Name: "ray", Age: 18},
+		})
+	})
+	server.GET("/unsupported", func(ctx *Context) {
+		ctx.Negotiate(http.StatusOK, Negotiate{Offered: []string{"image/png"}, Data: "ray"})
+	})
+
+	testCases := []struct {
+		name            string
+		path            string
+		accept          string
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "json",
+			path:            "/user",
+			accept:          "application/json",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"name":"ray"}`,
+		},
+		{
+			name:            "xml fallback data",
+			path:            "/user",
+			accept:          "application/xml",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/xml; charset=utf-8",
+			wantBody:        "<User><Name>ray</Name><Age>18</Age></User>",
+		},
+		{
+			name:            "text xml",
+			path:            "/user",
+			accept:          "text/xml",
+			wantCode:        http.StatusOK,
+			wantContentType: "text/xml; charset=utf-8",
+			wantBody:        "<User><Name>ray</Name><Age>18</Age></User>",
+		},
+		{
+			name:            "x-yaml",
+			path:            "/config",
+			accept:          "application/x-yaml",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/x-yaml; charset=utf-8",
+			wantBody:        "name: ray\n",
+		},
+		{
+			name:            "yaml",
+			path:            "/config",
+			accept:          "application/yaml",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/yaml; charset=utf-8",
+			wantBody:        "name: ray\n",
+		},
+		{
+			name:            "preset json",
+			path:            "/preset",
+			accept:          "application/json",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"name":"ray"}`,
+		},
+		{
+			name:            "preset xml",
+			path:            "/preset",
+			accept:          "application/xml",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/xml; charset=utf-8",
+			wantBody:        "<User><Name>ray</Name><Age>18</Age></User>",
+		},
+		{
+			name:            "preset yaml",
+			path:            "/preset",
+			accept:          "application/x-yaml",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/x-yaml; charset=utf-8",
+			wantBody:        "name: ray\n",
+		},
+		{
+			name:            "not acceptable",
+			path:            "/user",
+			accept:          "text/html",
+			wantCode:        http.StatusNotAcceptable,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        notAcceptable,
+		},
+		{
+			name:            "unsupported format",
+			path:            "/unsupported",
+			accept:          "image/png",
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        internalError,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			assert.NoError(t, err)
+			request.Header.Set("Accept", tt.accept)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantContentType, response.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, response.Body.String())
+			assert.Equal(t, []string{"Accept"}, response.Header().Values("Vary"))
+		})
+	}
+}
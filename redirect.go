@@ -0,0 +1,142 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrUnsafeRedirect 重定向地址不在 HttpServer.RedirectAllowedHosts 中，或者包含控制字符
+	ErrUnsafeRedirect = errors.New("web：不安全的重定向地址")
+	// ErrTooManyForwards Context.Forward 的次数超过 maxForwards，可能存在循环转发
+	ErrTooManyForwards = errors.New("web：转发次数过多")
+)
+
+// maxForwards 单个请求最多转发的次数
+const maxForwards = 10
+
+// Redirect 重定向到 location，code 必须为 3xx 重定向状态码或者 201
+// 相对地址会根据当前请求的路径解析为绝对路径
+// 设置了 HttpServer.RedirectAllowedHosts 时，指向其它站点的地址会被拒绝并响应 400，防止开放重定向
+func (c *Context) Redirect(code int, location string) {
+	if !validRedirectStatus(code) {
+		panic(fmt.Sprintf("web：无效的重定向状态码 %d", code))
+	}
+
+	target, err := c.resolveRedirect(location)
+	if err != nil {
+		c.Error(err)
+		c.String(http.StatusBadRequest, unsafeRedirect)
+		return
+	}
+	c.Writer.Header().Set("Location", target)
+	c.Status(code)
+	c.RespData = nil
+}
+
+// Forward 在服务内部将请求转发到 path 重新进行路由，不会返回给客户端
+// path 可以携带查询参数，相对路径根据当前请求的路径解析，全局 Middleware 不会再次执行
+func (c *Context) Forward(path string) {
+	if c.forwards >= maxForwards {
+		c.Error(ErrTooManyForwards)
+		c.internalError()
+		return
+	}
+	c.forwards++
+
+	target, err := c.Request.URL.Parse(path)
+	if err != nil || target.Host != "" {
+		c.Error(fmt.Errorf("web：无效的转发路径 %s", path))
+		c.internalError()
+		return
+	}
+	request := new(http.Request)
+	*request = *c.Request
+	request.URL = &url.URL{Path: target.Path, RawPath: target.RawPath, RawQuery: target.RawQuery}
+	request.RequestURI = request.URL.RequestURI()
+
+	c.Request = request
+	c.Params = c.Params[:0]
+	c.handler = nil
+	c.Route = ""
+	c.queryCache = nil
+	c.h.handleHttpRequest(c)
+}
+
+// resolveRedirect 解析重定向地址，返回写入 Location 的地址
+func (c *Context) resolveRedirect(location string) (string, error) {
+	if strings.IndexFunc(location, func(r rune) bool { return r < 0x20 || r == 0x7f }) >= 0 {
+		return "", ErrUnsafeRedirect
+	}
+	// 浏览器会将 \ 视为 /，例如 /\evil.com 会被当作 //evil.com
+	target, err := url.Parse(strings.ReplaceAll(location, "\\", "/"))
+	if err != nil {
+		return "", ErrUnsafeRedirect
+	}
+
+	// 相对地址
+	if target.Scheme == "" && target.Host == "" {
+		return c.Request.URL.ResolveReference(target).RequestURI() + fragment(target), nil
+	}
+
+	if c.h.RedirectAllowedHosts != nil {
+		if target.Scheme != "" && target.Scheme != "http" && target.Scheme != "https" {
+			return "", ErrUnsafeRedirect
+		}
+		if !c.allowedRedirectHost(target.Hostname()) {
+			return "", ErrUnsafeRedirect
+		}
+	}
+	return location, nil
+}
+
+// allowedRedirectHost 是否允许重定向到 host，当前请求的 Host 总是允许的
+// 支持 *.example.com 形式的子域名通配
+func (c *Context) allowedRedirectHost(host string) bool {
+	host = strings.ToLower(host)
+	if host == "" {
+		return false
+	}
+	requestHost := c.Request.Host
+	if h, _, err := net.SplitHostPort(requestHost); err == nil {
+		requestHost = h
+	}
+	if strings.EqualFold(host, requestHost) {
+		return true
+	}
+
+	for _, allowed := range c.h.RedirectAllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if strings.HasPrefix(allowed, "*.") {
+			if strings.HasSuffix(host, allowed[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func fragment(u *url.URL) string {
+	if u.Fragment == "" {
+		return ""
+	}
+	return "#" + u.EscapedFragment()
+}
+
+// validRedirectStatus 允许 300-308 中用于重定向的状态码和 201
+func validRedirectStatus(code int) bool {
+	switch code {
+	case http.StatusMultipleChoices, http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect, http.StatusCreated:
+		return true
+	}
+	return false
+}
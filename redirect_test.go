@@ -0,0 +1,212 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_Redirect(t *testing.T) {
+	testCases := []struct {
+		name         string
+		allowedHosts []string
+		path         string
+		code         int
+		location     string
+		wantCode     int
+		wantLocation string
+	}{
+		{
+			name:         "absolute path",
+			path:         "/user/login",
+			code:         http.StatusFound,
+			location:     "/home?tab=1",
+			wantCode:     http.StatusFound,
+			wantLocation: "/home?tab=1",
+		},
+		{
+			name:         "relative path",
+			path:         "/user/login",
+			code:         http.StatusSeeOther,
+			location:     "profile#info",
+			wantCode:     http.StatusSeeOther,
+			wantLocation: "/user/profile#info",
+		},
+		{
+			name:         "parent path",
+			path:         "/user/settings/email",
+			code:         http.StatusMovedPermanently,
+			location:     "../profile",
+			wantCode:     http.StatusMovedPermanently,
+			wantLocation: "/user/profile",
+		},
+		{
+			name:         "external without allow list",
+			path:         "/login",
+			code:         http.StatusTemporaryRedirect,
+			location:     "https://example.org/callback",
+			wantCode:     http.StatusTemporaryRedirect,
+			wantLocation: "https://example.org/callback",
+		},
+		{
+			name:         "allowed host",
+			allowedHosts: []string{"example.org"},
+			path:         "/login",
+			code:         http.StatusFound,
+			location:     "https://example.org/callback",
+			wantCode:     http.StatusFound,
+			wantLocation: "https://example.org/callback",
+		},
+		{
+			name:         "allowed subdomain",
+			allowedHosts: []string{"*.example.org"},
+			path:         "/login",
+			code:         http.StatusFound,
+			location:     "https://auth.example.org/callback",
+			wantCode:     http.StatusFound,
+			wantLocation: "https://auth.example.org/callback",
+		},
+		{
+			name:         "same host",
+			allowedHosts: []string{},
+			path:         "/login",
+			code:         http.StatusFound,
+			location:     "http://example.com/home",
+			wantCode:     http.StatusFound,
+			wantLocation: "http://example.com/home",
+		},
+		{
+			name:         "external host rejected",
+			allowedHosts: []string{"*.example.org"},
+			path:         "/login",
+			code:         http.StatusFound,
+			location:     "https://evil.com/?example.org",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			name:         "suffix host rejected",
+			allowedHosts: []string{"*.example.org"},
+			path:         "/login",
+			code:         http.StatusFound,
+			location:     "https://evilexample.org",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			name:         "scheme relative rejected",
+			allowedHosts: []string{},
+			path:         "/login",
+			code:         http.StatusFound,
+			location:     "//evil.com",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			name:         "backslash rejected",
+			allowedHosts: []string{},
+			path:         "/login",
+			code:         http.StatusFound,
+			location:     "/\\evil.com",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			name:         "javascript rejected",
+			allowedHosts: []string{},
+			path:         "/login",
+			code:         http.StatusFound,
+			location:     "javascript:alert(1)",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			name:     "control characters rejected",
+			path:     "/login",
+			code:     http.StatusFound,
+			location: "/home\r\nSet-Cookie: a=b",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := New()
+			server.RedirectAllowedHosts = tt.allowedHosts
+			server.GET(tt.path, func(ctx *Context) {
+				ctx.Redirect(tt.code, tt.location)
+			})
+			request := httptest.NewRequest(http.MethodGet, "http://example.com"+tt.path, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantLocation, response.Header().Get("Location"))
+			if tt.wantCode == http.StatusBadRequest {
+				assert.Equal(t, unsafeRedirect, response.Body.String())
+			}
+		})
+	}
+
+	assert.PanicsWithValue(t, "web：无效的重定向状态码 200", func() {
+		ctx := &Context{}
+		ctx.Redirect(http.StatusOK, "/")
+	})
+}
+
+func TestContext_Forward(t *testing.T) {
+	server := New()
+	server.Use(errorHandle())
+	server.GET("/old/:name", func(ctx *Context) {
+		ctx.Forward("/user/" + ctx.Param("name") + "?from=old")
+	})
+	server.GET("/relative", func(ctx *Context) {
+		ctx.Forward("user/ray")
+	})
+	server.GET("/user/:name", func(ctx *Context) {
+		ctx.String(http.StatusOK, "%s %s %s %s", ctx.Route, ctx.Param("name"), ctx.Query("from"), ctx.Request.URL.Path)
+	})
+	server.GET("/missing", func(ctx *Context) {
+		ctx.Forward("/not/found")
+	})
+	server.GET("/loop", func(ctx *Context) {
+		ctx.Forward("/loop")
+	})
+
+	testCases := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "forward",
+			path:     "/old/ray",
+			wantCode: http.StatusOK,
+			wantBody: "/user/:name ray old /user/ray",
+		},
+		{
+			name:     "relative",
+			path:     "/relative",
+			wantCode: http.StatusOK,
+			wantBody: "/user/:name ray  /user/ray",
+		},
+		{
+			name:     "not found",
+			path:     "/missing",
+			wantCode: http.StatusNotFound,
+			wantBody: notFound,
+		},
+		{
+			name:     "loop",
+			path:     "/loop",
+			wantCode: http.StatusInternalServerError,
+			wantBody: internalError,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+		})
+	}
+}
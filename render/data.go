@@ -0,0 +1,21 @@
+package render
+
+import "net/http"
+
+// Data 使用指定的 ContentType 写入原始数据
+type Data struct {
+	ContentType string
+	Data        []byte
+}
+
+// Render (Data) 写入原始数据
+func (r Data) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	_, err := w.Write(r.Data)
+	return err
+}
+
+// WriteContentType (Data) 写入自定义的数据类型
+func (r Data) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, []string{r.ContentType})
+}
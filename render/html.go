@@ -1,8 +1,16 @@
 package render
 
 import (
+	"errors"
+	"fmt"
 	"html/template"
+	"io/fs"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 )
 
 type HTML struct {
@@ -11,10 +19,65 @@ type HTML struct {
 	Data     any
 }
 
-var htmlContentType = []string{"text/html; charset=utf-8"}
+// HTMLRender 模版引擎接口，根据模版名称和数据创建 Render，可以替换为其它模版引擎
+type HTMLRender interface {
+	Instance(name string, data any) Render
+}
+
+// Delims 模版的左右分隔符
+type Delims struct {
+	Left  string
+	Right string
+}
+
+// HTMLProduction 预先解析好的模版
+type HTMLProduction struct {
+	Template *template.Template
+}
+
+// HTMLLayout 布局模式，每个页面与共享模版单独解析，渲染时执行 Layout 模版
+type HTMLLayout struct {
+	Layout string
+	Pages  map[string]*template.Template
+}
+
+// HTMLDebug 渲染前检查模版文件是否修改，修改后重新加载，适用于开发环境
+type HTMLDebug struct {
+	Loader HTMLLoader
+
+	mu        sync.Mutex
+	render    HTMLRender
+	signature string
+}
+
+// HTMLLoader 模版加载配置
+type HTMLLoader struct {
+	// FS 模版所在的文件系统，为 nil 时从本地文件系统加载
+	FS fs.FS
+	// Patterns 模版文件的 glob 表达式，也可以是文件名
+	Patterns []string
+	// Layout 布局模版名称，不为空时 Patterns 匹配的每个页面与 Shared 单独解析
+	// 渲染页面时执行 Layout 模版，页面通过 define 覆盖 Layout 中的 block
+	// 页面名称为相对于 pattern 中不含通配符的目录的路径，例如 views/*/*.tmpl 匹配的 admin/index.tmpl
+	// 名称重复时 Load 返回错误
+	Layout string
+	// Shared 布局模式下所有页面共享的模版，例如布局和局部模版
+	Shared  []string
+	FuncMap template.FuncMap
+	Delims  Delims
+}
+
+var (
+	htmlContentType = []string{"text/html; charset=utf-8"}
+
+	errNilTemplate = errors.New("render：HTML 模版为 nil，需要先加载模版")
+)
 
 // Render (HTML) 渲染模版并写回响应
 func (r HTML) Render(w http.ResponseWriter) error {
+	if r.Template == nil {
+		return errNilTemplate
+	}
 	r.WriteContentType(w)
 
 	if r.Name == "" {
@@ -27,3 +90,229 @@ func (r HTML) Render(w http.ResponseWriter) error {
 func (r HTML) WriteContentType(w http.ResponseWriter) {
 	writeContentType(w, htmlContentType)
 }
+
+// Instance (HTMLProduction) 创建渲染 name 模版的 HTML
+func (r HTMLProduction) Instance(name string, data any) Render {
+	return HTML{Template: r.Template, Name: name, Data: data}
+}
+
+// Instance (HTMLLayout) 创建在 Layout 中渲染 name 页面的 HTML
+func (r HTMLLayout) Instance(name string, data any) Render {
+	page, ok := r.Pages[name]
+	if !ok {
+		return errorRender{err: fmt.Errorf("render：页面模版 %s 不存在", name)}
+	}
+	return HTML{Template: page, Name: r.Layout, Data: data}
+}
+
+// Instance (HTMLDebug) 模版文件修改后重新加载，再创建 HTML
+func (r *HTMLDebug) Instance(name string, data any) Render {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.reload(false); err != nil {
+		return errorRender{err: err}
+	}
+	return r.render.Instance(name, data)
+}
+
+// Reload (HTMLDebug) 立即重新加载模版
+func (r *HTMLDebug) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reload(true)
+}
+
+func (r *HTMLDebug) reload(force bool) error {
+	signature, err := r.Loader.signature()
+	if err != nil {
+		return err
+	}
+	if !force && r.render != nil && signature == r.signature {
+		return nil
+	}
+	htmlRender, err := r.Loader.Load()
+	if err != nil {
+		return err
+	}
+	r.render, r.signature = htmlRender, signature
+	return nil
+}
+
+// Load 根据配置解析模版，Layout 为空时返回 HTMLProduction，否则返回 HTMLLayout
+func (l HTMLLoader) Load() (HTMLRender, error) {
+	if l.Layout == "" {
+		files, err := l.glob(l.Patterns)
+		if err != nil {
+			return nil, err
+		}
+		templ, err := l.parse(l.newTemplate(), files)
+		if err != nil {
+			return nil, err
+		}
+		return HTMLProduction{Template: templ}, nil
+	}
+
+	shared, err := l.glob(l.Shared)
+	if err != nil {
+		return nil, err
+	}
+	base := l.newTemplate()
+	if len(shared) > 0 {
+		if base, err = l.parse(base, shared); err != nil {
+			return nil, err
+		}
+	}
+	isShared := make(map[string]bool, len(shared))
+	for _, file := range shared {
+		isShared[file] = true
+	}
+	layout := HTMLLayout{Layout: l.Layout, Pages: make(map[string]*template.Template)}
+	files := make(map[string]string)
+	for _, pattern := range l.Patterns {
+		pages, err := l.glob([]string{pattern})
+		if err != nil {
+			return nil, err
+		}
+		root := globRoot(pattern, l.FS == nil)
+		for _, file := range pages {
+			if isShared[file] {
+				continue
+			}
+			name, err := l.rel(root, file)
+			if err != nil {
+				return nil, err
+			}
+			if other, ok := files[name]; ok {
+				if other == file {
+					continue
+				}
+				return nil, fmt.Errorf("render：页面模版 %s 与 %s 名称重复", file, other)
+			}
+			files[name] = file
+
+			page, err := base.Clone()
+			if err != nil {
+				return nil, err
+			}
+			if page, err = l.parse(page, []string{file}); err != nil {
+				return nil, err
+			}
+			layout.Pages[name] = page
+		}
+	}
+	return layout, nil
+}
+
+func (l HTMLLoader) newTemplate() *template.Template {
+	templ := template.New("").Delims(l.Delims.Left, l.Delims.Right)
+	if l.FuncMap != nil {
+		templ.Funcs(l.FuncMap)
+	}
+	return templ
+}
+
+func (l HTMLLoader) parse(templ *template.Template, files []string) (*template.Template, error) {
+	if l.FS == nil {
+		return templ.ParseFiles(files...)
+	}
+	return templ.ParseFS(l.FS, files...)
+}
+
+// rel 返回 file 相对于 root 的路径，使用 / 作为分隔符
+func (l HTMLLoader) rel(root, file string) (string, error) {
+	if l.FS == nil {
+		name, err := filepath.Rel(root, file)
+		return filepath.ToSlash(name), err
+	}
+	if root == "." {
+		return file, nil
+	}
+	return strings.TrimPrefix(file, root+"/"), nil
+}
+
+// globRoot 返回 pattern 中不包含通配符的目录部分，例如 views/*/*.tmpl 为 views
+func globRoot(pattern string, local bool) string {
+	if local {
+		pattern = filepath.ToSlash(pattern)
+	}
+	segments := strings.Split(pattern, "/")
+	root := make([]string, 0, len(segments))
+	for _, segment := range segments[:len(segments)-1] {
+		if strings.ContainsAny(segment, `*?[\`) {
+			break
+		}
+		root = append(root, segment)
+	}
+	if len(root) == 0 {
+		return "."
+	}
+	if local {
+		return filepath.FromSlash(strings.Join(root, "/"))
+	}
+	return strings.Join(root, "/")
+}
+
+// glob 返回 patterns 匹配的所有文件，按照名称排序并去重
+func (l HTMLLoader) glob(patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	files := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		var (
+			matches []string
+			err     error
+		)
+		if l.FS == nil {
+			matches, err = filepath.Glob(pattern)
+		} else {
+			matches, err = fs.Glob(l.FS, pattern)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("render：模版 %s 没有匹配的文件", pattern)
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// signature 根据模版文件的名称、大小和修改时间生成签名，用于判断模版是否修改
+func (l HTMLLoader) signature() (string, error) {
+	files, err := l.glob(append(append([]string{}, l.Shared...), l.Patterns...))
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, file := range files {
+		var info fs.FileInfo
+		if l.FS == nil {
+			info, err = os.Stat(file)
+		} else {
+			info, err = fs.Stat(l.FS, file)
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return sb.String(), nil
+}
+
+// errorRender 渲染时返回 err，用于延迟报告创建 Render 过程中发生的错误
+type errorRender struct {
+	err error
+}
+
+func (r errorRender) Render(http.ResponseWriter) error {
+	return r.err
+}
+
+func (r errorRender) WriteContentType(http.ResponseWriter) {}
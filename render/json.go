@@ -1,22 +1,63 @@
 package render
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
+	"unicode/utf8"
 )
 
 type JSON struct {
 	Data any
 }
 
-var jsonContentType = []string{"application/json; charset=utf-8"}
+// IndentedJSON 带缩进的 JSON，便于阅读
+type IndentedJSON struct {
+	Data any
+}
+
+// PureJSON 不转义 HTML 字符（<、>、&）的 JSON
+type PureJSON struct {
+	Data any
+}
+
+// SecureJSON 数据为数组时添加 Prefix 前缀，防止 JSON 劫持
+type SecureJSON struct {
+	Prefix string
+	Data   any
+}
+
+// AsciiJSON 非 ASCII 字符转义为 \uXXXX 的 JSON
+type AsciiJSON struct {
+	Data any
+}
+
+// JSONP 使用 Callback 包裹的 JSON，Callback 为空时按照 JSON 写入
+type JSONP struct {
+	Callback string
+	Data     any
+}
+
+var (
+	jsonContentType      = []string{"application/json; charset=utf-8"}
+	jsonASCIIContentType = []string{"application/json"}
+	jsonpContentType     = []string{"application/javascript; charset=utf-8"}
+)
+
+// ErrInvalidCallback JSONP 的 Callback 不是合法的 JavaScript 标识符
+var ErrInvalidCallback = errors.New("render：JSONP callback 不合法")
+
+// maxCallbackLength JSONP Callback 的最大长度
+const maxCallbackLength = 128
+
+// callbackPattern 允许 foo、$foo、_foo、foo.bar 等形式
+var callbackPattern = regexp.MustCompile(`^[a-zA-Z_$][0-9a-zA-Z_$]*(\.[a-zA-Z_$][0-9a-zA-Z_$]*)*$`)
 
 // Render (JSON) 使用自定义数据类型来写入
-func (r JSON) Render(w http.ResponseWriter) (err error) {
-	if err = WriteJSON(w, r.Data); err != nil {
-		panic(err)
-	}
-	return
+func (r JSON) Render(w http.ResponseWriter) error {
+	return WriteJSON(w, r.Data)
 }
 
 // WriteContentType (JSON) 写入 JSON 数据类型
@@ -27,10 +68,123 @@ func (r JSON) WriteContentType(w http.ResponseWriter) {
 // WriteJSON 序列化给定的对象，并将数据写入到响应中
 func WriteJSON(w http.ResponseWriter, obj any) error {
 	writeContentType(w, jsonContentType)
-	jsonBytes, err := json.Marshal(obj)
+	jsonBytes, err := Codec.Marshal(obj)
 	if err != nil {
 		return err
 	}
 	_, err = w.Write(jsonBytes)
 	return err
 }
+
+// Render (IndentedJSON) 序列化为带缩进的 JSON 并写回响应
+func (r IndentedJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	jsonBytes, err := Codec.MarshalIndent(r.Data, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(jsonBytes)
+	return err
+}
+
+// WriteContentType (IndentedJSON) 写入 JSON 数据类型
+func (r IndentedJSON) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonContentType)
+}
+
+// Render (PureJSON) 序列化为 JSON 并写回响应，HTML 字符保持原样
+func (r PureJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	encoder := Codec.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(r.Data)
+}
+
+// WriteContentType (PureJSON) 写入 JSON 数据类型
+func (r PureJSON) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonContentType)
+}
+
+// Render (SecureJSON) 序列化为 JSON，数据为数组时写入 Prefix 前缀
+func (r SecureJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	jsonBytes, err := Codec.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+	if bytes.HasPrefix(jsonBytes, []byte("[")) && bytes.HasSuffix(jsonBytes, []byte("]")) {
+		if _, err = w.Write([]byte(r.Prefix)); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write(jsonBytes)
+	return err
+}
+
+// WriteContentType (SecureJSON) 写入 JSON 数据类型
+func (r SecureJSON) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonContentType)
+}
+
+// Render (AsciiJSON) 序列化为 JSON，非 ASCII 字符转义后写回响应
+func (r AsciiJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	jsonBytes, err := Codec.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(jsonBytes))
+	for len(jsonBytes) > 0 {
+		c, size := utf8.DecodeRune(jsonBytes)
+		jsonBytes = jsonBytes[size:]
+		if c < utf8.RuneSelf {
+			buf.WriteByte(byte(c))
+			continue
+		}
+		// 超出基本多文种平面的字符使用 UTF-16 代理对表示
+		if c > 0xFFFF {
+			c -= 0x10000
+			fmt.Fprintf(&buf, `\u%04x\u%04x`, 0xD800+(c>>10), 0xDC00+(c&0x3FF))
+			continue
+		}
+		fmt.Fprintf(&buf, `\u%04x`, c)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// WriteContentType (AsciiJSON) 写入 JSON 数据类型
+func (r AsciiJSON) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonASCIIContentType)
+}
+
+// Render (JSONP) 使用 Callback 包裹 JSON 写回响应，Callback 不合法时返回 ErrInvalidCallback
+func (r JSONP) Render(w http.ResponseWriter) error {
+	if r.Callback == "" {
+		return WriteJSON(w, r.Data)
+	}
+	if !ValidCallback(r.Callback) {
+		return ErrInvalidCallback
+	}
+	r.WriteContentType(w)
+
+	jsonBytes, err := Codec.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+	// 前置注释防止响应被当作其它格式（例如 Flash）解析
+	_, err = fmt.Fprintf(w, "/**/%s(%s);", r.Callback, jsonBytes)
+	return err
+}
+
+// WriteContentType (JSONP) 写入 JavaScript 数据类型
+func (r JSONP) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonpContentType)
+}
+
+// ValidCallback 校验 JSONP Callback 是否为合法的 JavaScript 标识符
+func ValidCallback(callback string) bool {
+	return len(callback) <= maxCallbackLength && callbackPattern.MatchString(callback)
+}
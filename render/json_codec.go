@@ -0,0 +1,38 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONCodec JSON 编码器，替换 Codec 可以全局切换 JSON 系列渲染使用的实现
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	MarshalIndent(v any, prefix, indent string) ([]byte, error)
+	NewEncoder(w io.Writer) JSONEncoder
+}
+
+// JSONEncoder 流式 JSON 编码器，与 json.Encoder 的方法保持一致
+type JSONEncoder interface {
+	SetEscapeHTML(on bool)
+	SetIndent(prefix, indent string)
+	Encode(v any) error
+}
+
+// Codec JSON 系列渲染使用的编码器，默认为标准库 encoding/json
+// 需要在启动服务前设置，运行过程中替换不是并发安全的
+var Codec JSONCodec = stdJSONCodec{}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(v, prefix, indent)
+}
+
+func (stdJSONCodec) NewEncoder(w io.Writer) JSONEncoder {
+	return json.NewEncoder(w)
+}
@@ -0,0 +1,29 @@
+package render
+
+import (
+	"github.com/ugorji/go/codec"
+	"net/http"
+)
+
+type MsgPack struct {
+	Data any
+}
+
+var msgpackContentType = []string{"application/msgpack"}
+
+// Render (MsgPack) 使用 MessagePack 编码给定的对象，并将数据写入到响应中
+func (r MsgPack) Render(w http.ResponseWriter) error {
+	return WriteMsgPack(w, r.Data)
+}
+
+// WriteContentType (MsgPack) 写入 MessagePack 数据类型
+func (r MsgPack) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, msgpackContentType)
+}
+
+// WriteMsgPack 使用 MessagePack 编码给定的对象，并将数据写入到响应中
+func WriteMsgPack(w http.ResponseWriter, obj any) error {
+	writeContentType(w, msgpackContentType)
+	var mh codec.MsgpackHandle
+	return codec.NewEncoder(w, &mh).Encode(obj)
+}
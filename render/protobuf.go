@@ -1,7 +1,8 @@
 package render
 
 import (
-	"github.com/golang/protobuf/proto"
+	"errors"
+	"google.golang.org/protobuf/proto"
 	"net/http"
 )
 
@@ -11,11 +12,17 @@ type ProtoBuf struct {
 
 var protobufContentType = []string{"application/x-protobuf"}
 
+var errNotProtoMessage = errors.New("render：Data 不是 proto.Message")
+
 // Render (ProtoBuf) 序列化给定的接口并写回响应
 func (r ProtoBuf) Render(w http.ResponseWriter) error {
 	r.WriteContentType(w)
 
-	bytes, err := proto.Marshal(r.Data.(proto.Message))
+	msg, ok := r.Data.(proto.Message)
+	if !ok {
+		return errNotProtoMessage
+	}
+	bytes, err := proto.Marshal(msg)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,34 @@
+package render
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"net/http"
+)
+
+// ProtoJSON 使用 protobuf 的 JSON 映射规则序列化 proto.Message
+type ProtoJSON struct {
+	Data any
+}
+
+// Render (ProtoJSON) 序列化给定的 proto.Message 并写回响应
+func (r ProtoJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	msg, ok := r.Data.(proto.Message)
+	if !ok {
+		return errNotProtoMessage
+	}
+	bytes, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(bytes)
+	return err
+}
+
+// WriteContentType (ProtoJSON) 写入 JSON 数据类型
+func (r ProtoJSON) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonContentType)
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Reader 从 Reader 读取数据写回响应，ContentLength 小于 0 时不设置 Content-Length
+type Reader struct {
+	ContentType   string
+	ContentLength int64
+	Reader        io.Reader
+	Headers       map[string]string
+}
+
+// Render (Reader) 写入响应头，并将 Reader 中的数据复制到响应中
+func (r Reader) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	header := w.Header()
+	if r.ContentLength >= 0 {
+		header.Set("Content-Length", strconv.FormatInt(r.ContentLength, 10))
+	}
+	for key, val := range r.Headers {
+		if header.Get(key) == "" {
+			header.Set(key, val)
+		}
+	}
+	_, err := io.Copy(w, r.Reader)
+	return err
+}
+
+// WriteContentType (Reader) 写入自定义的数据类型
+func (r Reader) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, []string{r.ContentType})
+}
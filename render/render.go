@@ -10,9 +10,27 @@ type Render interface {
 // 校验是否符合该接口
 var (
 	_ Render = JSON{}
+	_ Render = IndentedJSON{}
+	_ Render = PureJSON{}
+	_ Render = SecureJSON{}
+	_ Render = AsciiJSON{}
+	_ Render = JSONP{}
+	_ Render = XML{}
 	_ Render = String{}
 	_ Render = HTML{}
 	_ Render = ProtoBuf{}
+	_ Render = ProtoJSON{}
+	_ Render = YAML{}
+	_ Render = TOML{}
+	_ Render = MsgPack{}
+	_ Render = Data{}
+	_ Render = Reader{}
+)
+
+var (
+	_ HTMLRender = HTMLProduction{}
+	_ HTMLRender = HTMLLayout{}
+	_ HTMLRender = (*HTMLDebug)(nil)
 )
 
 func writeContentType(w http.ResponseWriter, value []string) {
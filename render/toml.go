@@ -0,0 +1,30 @@
+package render
+
+import (
+	"github.com/pelletier/go-toml/v2"
+	"net/http"
+)
+
+type TOML struct {
+	Data any
+}
+
+var tomlContentType = []string{"application/toml; charset=utf-8"}
+
+// Render (TOML) 序列化给定的对象，并将数据写入到响应中
+func (r TOML) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	bytes, err := toml.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(bytes)
+	return err
+}
+
+// WriteContentType (TOML) 写入 TOML 数据类型
+func (r TOML) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, tomlContentType)
+}
@@ -0,0 +1,23 @@
+package render
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+type XML struct {
+	Data any
+}
+
+var xmlContentType = []string{"application/xml; charset=utf-8"}
+
+// Render (XML) 将数据序列化为 XML 并写回响应
+func (r XML) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return xml.NewEncoder(w).Encode(r.Data)
+}
+
+// WriteContentType (XML) 写入 XML 数据类型
+func (r XML) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, xmlContentType)
+}
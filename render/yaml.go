@@ -0,0 +1,30 @@
+package render
+
+import (
+	"gopkg.in/yaml.v3"
+	"net/http"
+)
+
+type YAML struct {
+	Data any
+}
+
+var yamlContentType = []string{"application/yaml; charset=utf-8"}
+
+// Render (YAML) 序列化给定的对象，并将数据写入到响应中
+func (r YAML) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	bytes, err := yaml.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(bytes)
+	return err
+}
+
+// WriteContentType (YAML) 写入 YAML 数据类型
+func (r YAML) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, yamlContentType)
+}
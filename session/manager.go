@@ -13,13 +13,9 @@ type Manager struct {
 
 // GetSession 获取 Session
 func (m *Manager) GetSession(ctx *web.Context) (Session, error) {
-	if ctx.UserValues == nil {
-		ctx.UserValues = make(map[string]any, 1)
-	}
-
 	// 先从缓存获取
-	if v, ok := ctx.UserValues[m.SessCtxKey]; ok {
-		return v.(Session), nil
+	if sess, ok := web.Value[Session](ctx, m.SessCtxKey); ok {
+		return sess, nil
 	}
 
 	// 从请求中提取到 session id
@@ -32,13 +28,14 @@ func (m *Manager) GetSession(ctx *web.Context) (Session, error) {
 		return nil, err
 	} else {
 		// 进行缓存
-		ctx.UserValues[m.SessCtxKey] = session
+		ctx.Set(m.SessCtxKey, session)
 		return session, nil
 	}
 }
 
 // InitSession 初始化 session
-func (m *Manager) InitSession(ctx web.Context, id string) (Session, error) {
+// ctx 需要传入指针，web.Context 包含 sync.RWMutex，不能复制
+func (m *Manager) InitSession(ctx *web.Context, id string) (Session, error) {
 	session, err := m.Generate(ctx.Request.Context(), id)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,262 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const indexPage = "index.html"
+
+// StaticConfig 静态文件服务配置
+type StaticConfig struct {
+	// FS 静态文件所在的文件系统，可以使用 os.DirFS 或 embed.FS
+	FS fs.FS
+	// Browse 目录下没有 index.html 时是否列出目录内容，默认响应 404
+	Browse bool
+	// Precompressed 客户端支持时优先响应预先压缩的 .br、.gz 文件，例如 app.js.br
+	Precompressed bool
+	// CacheControl 按照扩展名设置 Cache-Control，例如 ".js": "public, max-age=31536000"
+	CacheControl map[string]string
+}
+
+// precompressedEncodings 预压缩文件的编码和扩展名，按照优先级排列
+var precompressedEncodings = []struct {
+	encoding string
+	ext      string
+}{
+	{encoding: "br", ext: ".br"},
+	{encoding: "gzip", ext: ".gz"},
+}
+
+// Static 将本地目录 root 注册为静态文件服务，例如 Static("/assets", "./public")
+func (r *RouterGroup) Static(prefix, root string) IRoutes {
+	return r.StaticFS(prefix, os.DirFS(root))
+}
+
+// StaticFS 将 fs.FS 注册为静态文件服务，支持 embed.FS
+func (r *RouterGroup) StaticFS(prefix string, fsys fs.FS) IRoutes {
+	return r.StaticWithConfig(prefix, StaticConfig{FS: fsys})
+}
+
+// StaticWithConfig 根据配置注册静态文件服务，请求路径中 prefix 之后的部分作为文件名
+func (r *RouterGroup) StaticWithConfig(prefix string, config StaticConfig) IRoutes {
+	if strings.ContainsAny(prefix, ":*") {
+		panic("web：静态文件服务路径不允许包含参数和通配符")
+	}
+	if config.FS == nil {
+		panic("web：静态文件服务 FS 为 nil")
+	}
+
+	handler := func(ctx *Context) {
+		config.serve(ctx, ctx.Param("filepath"))
+	}
+	urlPattern := path.Join(prefix, "/*filepath")
+	r.GET(urlPattern, handler)
+	r.HEAD(urlPattern, handler)
+	return r
+}
+
+// StaticFile 将本地文件注册为单个静态文件，例如 StaticFile("/favicon.ico", "./public/favicon.ico")
+func (r *RouterGroup) StaticFile(relativePath, file string) IRoutes {
+	return r.StaticFileFS(relativePath, filepath.Base(file), os.DirFS(filepath.Dir(file)))
+}
+
+// StaticFileFS 将 fs.FS 中的 name 文件注册为单个静态文件
+func (r *RouterGroup) StaticFileFS(relativePath, name string, fsys fs.FS) IRoutes {
+	if strings.ContainsAny(relativePath, ":*") {
+		panic("web：静态文件服务路径不允许包含参数和通配符")
+	}
+
+	config := StaticConfig{FS: fsys}
+	handler := func(ctx *Context) {
+		config.serveFile(ctx, name)
+	}
+	r.GET(relativePath, handler)
+	r.HEAD(relativePath, handler)
+	return r
+}
+
+// serve 处理静态文件请求，urlPath 为 / 开头的文件路径
+func (s StaticConfig) serve(ctx *Context, urlPath string) {
+	name, ok := cleanName(urlPath)
+	if !ok {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+
+	info, err := fs.Stat(s.FS, name)
+	if err != nil {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if !info.IsDir() {
+		s.serveFile(ctx, name)
+		return
+	}
+
+	// 目录需要以 / 结尾，保证页面中的相对路径正确
+	if !strings.HasSuffix(ctx.Request.URL.Path, "/") {
+		target := ctx.Request.URL.Path + "/"
+		if ctx.Request.URL.RawQuery != "" {
+			target += "?" + ctx.Request.URL.RawQuery
+		}
+		ctx.Header("Location", target)
+		ctx.Status(http.StatusMovedPermanently)
+		return
+	}
+	index := path.Join(name, indexPage)
+	if info, err := fs.Stat(s.FS, index); err == nil && !info.IsDir() {
+		s.serveFile(ctx, index)
+		return
+	}
+	if !s.Browse {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	s.listDir(ctx, name)
+}
+
+// cleanName 将请求路径转换为 fs.FS 中的文件名，根目录为 .
+func cleanName(urlPath string) (string, bool) {
+	// 拒绝 Windows 路径分隔符和空字符，Clean 之后不会再出现 ..，无法访问根目录之外的文件
+	if strings.ContainsAny(urlPath, "\\\x00") {
+		return "", false
+	}
+	name := strings.TrimPrefix(path.Clean("/"+urlPath), "/")
+	if name == "" {
+		name = "."
+	}
+	return name, fs.ValidPath(name)
+}
+
+// serveFile 响应文件内容，由 http.ServeContent 处理 Range、If-None-Match、If-Modified-Since 等请求头
+func (s StaticConfig) serveFile(ctx *Context, name string) {
+	info, err := fs.Stat(s.FS, name)
+	if err != nil || info.IsDir() {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+
+	header := ctx.Writer.Header()
+	contentType := mime.TypeByExtension(path.Ext(name))
+	servedName, servedInfo, encoding := name, info, ""
+	if s.Precompressed {
+		header.Add("Vary", "Accept-Encoding")
+		acceptEncoding := ctx.Request.Header.Get("Accept-Encoding")
+		for _, pe := range precompressedEncodings {
+			if !acceptsEncoding(acceptEncoding, pe.encoding) {
+				continue
+			}
+			if info, err := fs.Stat(s.FS, name+pe.ext); err == nil && !info.IsDir() {
+				servedName, servedInfo, encoding = name+pe.ext, info, pe.encoding
+				break
+			}
+		}
+	}
+
+	file, err := s.FS.Open(servedName)
+	if err != nil {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+	content, err := readSeeker(file)
+	if err != nil {
+		ctx.Error(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+		// 压缩文件的类型需要根据原始文件确定，否则会被识别为压缩包
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+	}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	if cacheControl, ok := s.CacheControl[strings.ToLower(path.Ext(name))]; ok {
+		header.Set("Cache-Control", cacheControl)
+	}
+	header.Set("ETag", etag(servedInfo, encoding))
+
+	http.ServeContent(newStreamWriter(ctx), ctx.Request, name, servedInfo.ModTime(), content)
+}
+
+// listDir 列出目录内容
+func (s StaticConfig) listDir(ctx *Context, name string) {
+	entries, err := fs.ReadDir(s.FS, name)
+	if err != nil {
+		ctx.Error(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("<!doctype html>\n<pre>\n")
+	for _, entry := range entries {
+		entryName := entry.Name()
+		if entry.IsDir() {
+			entryName += "/"
+		}
+		link := url.URL{Path: entryName}
+		fmt.Fprintf(&buf, "<a href=\"%s\">%s</a>\n", link.String(), template.HTMLEscapeString(entryName))
+	}
+	buf.WriteString("</pre>\n")
+
+	ctx.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	ctx.WriteWithStatus(http.StatusOK, buf.Bytes())
+}
+
+// etag 根据文件大小和修改时间生成 ETag，不同编码的文件使用不同的 ETag
+func etag(info fs.FileInfo, encoding string) string {
+	tag := strconv.FormatInt(info.ModTime().UnixNano(), 16) + "-" + strconv.FormatInt(info.Size(), 16)
+	if encoding != "" {
+		tag += "-" + encoding
+	}
+	return `"` + tag + `"`
+}
+
+// acceptsEncoding 请求的 Accept-Encoding 是否接受 encoding，q=0 表示不接受
+func acceptsEncoding(acceptEncoding, encoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), encoding) {
+			continue
+		}
+		for _, param := range params[1:] {
+			key, val, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.EqualFold(strings.TrimSpace(key), "q") {
+				q, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+				return err == nil && q > 0
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// readSeeker http.ServeContent 需要 io.ReadSeeker，不支持 Seek 的文件读取到内存中
+func readSeeker(file fs.File) (io.ReadSeeker, error) {
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		return seeker, nil
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
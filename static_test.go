@@ -0,0 +1,230 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestRouterGroup_Static(t *testing.T) {
+	modTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	assets := fstest.MapFS{
+		"app.js":          {Data: []byte("console.log('app')"), ModTime: modTime},
+		"app.js.gz":       {Data: []byte("gzip app"), ModTime: modTime},
+		"app.js.br":       {Data: []byte("br app"), ModTime: modTime},
+		"style.css":       {Data: []byte("body{}"), ModTime: modTime},
+		"docs/index.html": {Data: []byte("<h1>docs</h1>"), ModTime: modTime},
+		"images/a.png":    {Data: []byte("png"), ModTime: modTime},
+		"images/<b>.png":  {Data: []byte("png"), ModTime: modTime},
+	}
+
+	server := New()
+	server.StaticFS("/embed", assets)
+	server.Group("/assets").StaticWithConfig("/", StaticConfig{
+		FS:            assets,
+		Browse:        true,
+		Precompressed: true,
+		CacheControl:  map[string]string{".js": "public, max-age=31536000"},
+	})
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		header     map[string]string
+		wantCode   int
+		wantBody   string
+		wantHeader map[string]string
+	}{
+		{
+			name:     "file",
+			path:     "/embed/style.css",
+			wantCode: http.StatusOK,
+			wantBody: "body{}",
+			wantHeader: map[string]string{
+				"Content-Type":  "text/css; charset=utf-8",
+				"Last-Modified": "Sun, 01 Jan 2023 00:00:00 GMT",
+				"Cache-Control": "",
+			},
+		},
+		{
+			name:     "head",
+			method:   http.MethodHead,
+			path:     "/embed/style.css",
+			wantCode: http.StatusOK,
+			wantHeader: map[string]string{
+				"Content-Length": "6",
+			},
+		},
+		{
+			name:     "if modified since",
+			path:     "/embed/style.css",
+			header:   map[string]string{"If-Modified-Since": "Sun, 01 Jan 2023 00:00:00 GMT"},
+			wantCode: http.StatusNotModified,
+		},
+		{
+			name:     "range",
+			path:     "/embed/app.js",
+			header:   map[string]string{"Range": "bytes=0-6"},
+			wantCode: http.StatusPartialContent,
+			wantBody: "console",
+			wantHeader: map[string]string{
+				"Content-Range": "bytes 0-6/18",
+			},
+		},
+		{
+			name:     "directory index",
+			path:     "/embed/docs/",
+			wantCode: http.StatusOK,
+			wantBody: "<h1>docs</h1>",
+		},
+		{
+			name:     "directory redirect",
+			path:     "/embed/docs",
+			wantCode: http.StatusMovedPermanently,
+			wantHeader: map[string]string{
+				"Location": "/embed/docs/",
+			},
+		},
+		{
+			name:     "directory listing disabled",
+			path:     "/embed/images/",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "directory listing",
+			path:     "/assets/images/",
+			wantCode: http.StatusOK,
+			wantBody: "<!doctype html>\n<pre>\n<a href=\"%3Cb%3E.png\">&lt;b&gt;.png</a>\n<a href=\"a.png\">a.png</a>\n</pre>\n",
+		},
+		{
+			name:     "not found",
+			path:     "/embed/missing.js",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "traversal",
+			path:     "/embed/../web.go",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "backslash",
+			path:     "/embed/docs\\..\\app.js",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "brotli",
+			path:     "/assets/app.js",
+			header:   map[string]string{"Accept-Encoding": "gzip, br"},
+			wantCode: http.StatusOK,
+			wantBody: "br app",
+			wantHeader: map[string]string{
+				"Content-Encoding": "br",
+				"Content-Type":     "text/javascript; charset=utf-8",
+				"Vary":             "Accept-Encoding",
+				"Cache-Control":    "public, max-age=31536000",
+			},
+		},
+		{
+			name:     "gzip",
+			path:     "/assets/app.js",
+			header:   map[string]string{"Accept-Encoding": "gzip, br;q=0"},
+			wantCode: http.StatusOK,
+			wantBody: "gzip app",
+			wantHeader: map[string]string{
+				"Content-Encoding": "gzip",
+			},
+		},
+		{
+			name:     "identity",
+			path:     "/assets/app.js",
+			wantCode: http.StatusOK,
+			wantBody: "console.log('app')",
+			wantHeader: map[string]string{
+				"Content-Encoding": "",
+				"Vary":             "Accept-Encoding",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			request := httptest.NewRequest(method, "/", nil)
+			request.URL.Path = tt.path
+			for key, val := range tt.header {
+				request.Header.Set(key, val)
+			}
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+			for key, val := range tt.wantHeader {
+				assert.Equal(t, val, response.Header().Get(key), key)
+			}
+		})
+	}
+}
+
+func TestRouterGroup_StaticETag(t *testing.T) {
+	server := New()
+	server.StaticFS("/", fstest.MapFS{"app.js": {Data: []byte("app"), ModTime: time.Now()}})
+
+	request := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+	etag := response.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	request = httptest.NewRequest(http.MethodGet, "/app.js", nil)
+	request.Header.Set("If-None-Match", etag)
+	response = httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotModified, response.Code)
+	assert.Empty(t, response.Body.String())
+}
+
+func TestRouterGroup_StaticLocal(t *testing.T) {
+	dir := t.TempDir()
+	public := filepath.Join(dir, "public")
+	require.NoError(t, os.Mkdir(public, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(public, "hello.txt"), []byte("hello"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0o644))
+
+	server := New()
+	server.Static("/public", public)
+	server.StaticFile("/favicon.txt", filepath.Join(public, "hello.txt"))
+
+	testCases := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "static", path: "/public/hello.txt", wantCode: http.StatusOK, wantBody: "hello"},
+		{name: "static file", path: "/favicon.txt", wantCode: http.StatusOK, wantBody: "hello"},
+		{name: "traversal", path: "/public/../secret.txt", wantCode: http.StatusNotFound},
+		{name: "encoded traversal", path: "/public/%2e%2e/secret.txt", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.URL.Path = tt.path
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+		})
+	}
+}
@@ -67,6 +67,7 @@ type node struct {
 	children    map[string]*node // 普通的孩子节点，使用 map 快速查找
 	starChild   *node            // 通配符匹配
 	paramChild  *node            // 参数匹配
+	anyChild    *node            // 匹配剩余的所有路径，例如 /static/*filepath
 	middlewares []Middleware     // 路由局部 Middleware，例如 Group 方法添加的 Middleware
 	handler     HandleFunc       // 业务处理器
 	fullPath    string           // 注册路由绑定的路径
@@ -76,6 +77,7 @@ type node struct {
 //  1. 静态路由：/a/b/c
 //  2. 通配符路由：/a/*
 //  3. 参数路由：/a/:name
+//  4. 全路径通配路由：/a/*filepath，只能位于路由末尾，参数值为 / 开头的剩余路径
 //
 // 通配符与参数路由是互斥的，要么存在通配符路由，要么存在参数路由
 //
@@ -108,10 +110,13 @@ func (n *node) addRoute(path string, middlewares []Middleware, handler HandleFun
 
 	cur := n
 	segments := strings.Split(path[1:], "/")
-	for _, seg := range segments {
+	for i, seg := range segments {
 		if seg == "" {
 			panic("web：拒绝 /a//b/c 形式的路由")
 		}
+		if isCatchAll(seg) && i != len(segments)-1 {
+			panic("web：全路径通配符只能位于路由末尾")
+		}
 		cur = cur.insert(seg)
 	}
 
@@ -126,8 +131,25 @@ func (n *node) addRoute(path string, middlewares []Middleware, handler HandleFun
 // insert 插入节点
 // 通配符和参数匹配只允许存在一个
 func (n *node) insert(path string) *node {
+	// 全路径通配路由：/a/*filepath
+	if isCatchAll(path) {
+		if n.paramChild != nil || n.starChild != nil {
+			panic("web：全路径通配符与通配符、参数匹配冲突，只允许存在一个")
+		}
+		if n.anyChild != nil && n.anyChild.path != path {
+			panic("web：全路径通配符名称冲突[" + n.anyChild.path + "]")
+		}
+		if n.anyChild == nil {
+			n.anyChild = &node{path: path}
+		}
+		return n.anyChild
+	}
+
 	// 通配符路由：/a/*/b
 	if path == "*" {
+		if n.anyChild != nil {
+			panic("web：全路径通配符与通配符、参数匹配冲突，只允许存在一个")
+		}
 		if n.paramChild != nil {
 			panic("web：通配符与参数匹配冲突，只允许存在一个")
 		}
@@ -139,6 +161,9 @@ func (n *node) insert(path string) *node {
 
 	// 参数路由：/a/:name
 	if path[0] == ':' {
+		if n.anyChild != nil {
+			panic("web：全路径通配符与通配符、参数匹配冲突，只允许存在一个")
+		}
 		if n.starChild != nil {
 			panic("web：通配符与参数匹配冲突，只允许存在一个")
 		}
@@ -163,6 +188,9 @@ func (n *node) insert(path string) *node {
 // findRoute 查找路由
 func (n *node) findRoute(path string) (*nodeInfo, bool) {
 	if path == "/" {
+		if n.handler == nil && n.anyChild != nil {
+			return n.anyChild.toNodeInfo(Params{{key: n.anyChild.path[1:], value: "/"}})
+		}
 		return n.toNodeInfo(nil)
 	}
 
@@ -170,13 +198,22 @@ func (n *node) findRoute(path string) (*nodeInfo, bool) {
 	segments := strings.Split(strings.Trim(path, "/"), "/")
 	cur := n
 	params := Params{}
-	for _, seg := range segments {
+	for i, seg := range segments {
 		// 禁止 /a//b 路由场景
 		if seg == "" {
 			return &nodeInfo{}, false
 		}
 		res, matchParam, ok := cur.child(seg)
 		if !ok {
+			// 匹配剩余的所有路径，保留结尾的 /
+			if cur.anyChild != nil {
+				value := "/" + strings.Join(segments[i:], "/")
+				if path[len(path)-1] == '/' {
+					value += "/"
+				}
+				params = append(params, Param{key: cur.anyChild.path[1:], value: value})
+				return cur.anyChild.toNodeInfo(params)
+			}
 			return &nodeInfo{}, false
 		}
 		// 匹配到参数
@@ -186,9 +223,19 @@ func (n *node) findRoute(path string) (*nodeInfo, bool) {
 		cur = res
 	}
 
+	// /static/ 匹配 /static/*filepath，参数值为 /
+	if cur.handler == nil && cur.anyChild != nil {
+		params = append(params, Param{key: cur.anyChild.path[1:], value: "/"})
+		return cur.anyChild.toNodeInfo(params)
+	}
 	return cur.toNodeInfo(params)
 }
 
+// isCatchAll 是否为全路径通配符，例如 *filepath
+func isCatchAll(seg string) bool {
+	return len(seg) > 1 && seg[0] == '*'
+}
+
 // child 搜索节点，不支持路由回溯（往回查找）
 // 节点匹配优先级：静态路由 > 参数路由 > 通配符路由
 func (n *node) child(path string) (*node, bool, bool) {
@@ -48,6 +48,61 @@ func TestTreeAddAndGet(t *testing.T) {
 	})
 }
 
+func TestTreeCatchAll(t *testing.T) {
+	n := &node{}
+	handler := func(ctx *Context) {}
+
+	n.addRoute("/static/*filepath", nil, handler)
+	n.addRoute("/static/index", nil, handler)
+	n.addRoute("/user/:name/files/*path", nil, handler)
+
+	checkRequests(t, n, testRequests{
+		{"/static/index", false, "/static/index", nil},
+		{"/static/css/app.css", false, "/static/*filepath", Params{
+			Param{"filepath", "/css/app.css"},
+		}},
+		{"/static/css/", false, "/static/*filepath", Params{
+			Param{"filepath", "/css/"},
+		}},
+		{"/static/", false, "/static/*filepath", Params{
+			Param{"filepath", "/"},
+		}},
+		{"/static", false, "/static/*filepath", Params{
+			Param{"filepath", "/"},
+		}},
+		{"/user/ray/files/a/b", false, "/user/:name/files/*path", Params{
+			Param{"name", "ray"},
+			Param{"path", "/a/b"},
+		}},
+		{"/user/ray", true, "", nil},
+	})
+
+	root := &node{}
+	root.addRoute("/*filepath", nil, handler)
+	checkRequests(t, root, testRequests{
+		{"/", false, "/*filepath", Params{
+			Param{"filepath", "/"},
+		}},
+		{"/favicon.ico", false, "/*filepath", Params{
+			Param{"filepath", "/favicon.ico"},
+		}},
+	})
+
+	assert.PanicsWithValue(t, "web：全路径通配符只能位于路由末尾", func() {
+		n.addRoute("/assets/*filepath/raw", nil, handler)
+	})
+	assert.PanicsWithValue(t, "web：全路径通配符名称冲突[*filepath]", func() {
+		n.addRoute("/static/*name", nil, handler)
+	})
+	assert.PanicsWithValue(t, "web：全路径通配符与通配符、参数匹配冲突，只允许存在一个", func() {
+		n.addRoute("/static/:name", nil, handler)
+	})
+	assert.PanicsWithValue(t, "web：全路径通配符与通配符、参数匹配冲突，只允许存在一个", func() {
+		n.addRoute("/user/:name/*path", nil, handler)
+		n.addRoute("/user/:name/*", nil, handler)
+	})
+}
+
 func TestTreePanic(t *testing.T) {
 	n := &node{}
 	handler := func(ctx *Context) {}
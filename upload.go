@@ -0,0 +1,299 @@
+package web
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrFileTooLarge 上传的单个文件超过 UploadConfig.MaxFileSize
+	ErrFileTooLarge = errors.New("web：上传的文件超出大小限制")
+	// ErrUploadTooLarge 上传的请求 body 超过 UploadConfig.MaxTotalSize
+	ErrUploadTooLarge = errors.New("web：上传的数据超出大小限制")
+	// ErrFileTypeNotAllowed 上传的文件类型不在 UploadConfig.AllowedTypes 中
+	ErrFileTypeNotAllowed = errors.New("web：上传的文件类型不允许")
+)
+
+// sniffLen http.DetectContentType 最多读取的字节数
+const sniffLen = 512
+
+// maxFilenameLength 文件名的最大字节数，大多数文件系统限制为 255
+const maxFilenameLength = 255
+
+// UploadConfig 流式读取 multipart 表单的配置
+type UploadConfig struct {
+	// MaxFileSize 单个文件的最大字节数，0 表示不限制
+	MaxFileSize int64
+	// MaxTotalSize 整个请求 body 的最大字节数，0 表示不限制
+	MaxTotalSize int64
+	// AllowedTypes 允许上传的文件类型，根据文件内容识别，例如 image/png，为空表示不限制
+	AllowedTypes []string
+}
+
+// UploadReader 逐个读取 multipart 表单中的 part，文件内容不会缓存到内存或者临时文件中
+type UploadReader struct {
+	config UploadConfig
+	body   *countingReader
+	reader *multipart.Reader
+	part   *UploadPart
+}
+
+// UploadPart multipart 表单中的一个字段或者文件
+type UploadPart struct {
+	// FormName 表单字段名称
+	FormName string
+	// FileName 清理之后的文件名，可以安全地作为本地文件名，普通字段为空
+	FileName string
+	// RawFileName 客户端提交的原始文件名，不要直接用于拼接路径
+	RawFileName string
+	// ContentType 根据内容识别的类型，客户端声明的类型在 Header 中
+	ContentType string
+	Header      textproto.MIMEHeader
+
+	reader *UploadReader
+	buf    *bufio.Reader
+	size   int64
+}
+
+// MultipartReader 创建流式读取 multipart 表单的 UploadReader，适用于上传大文件
+// 与 MultipartForm、FormFile 互斥，请求 body 只能被读取一次
+func (c *Context) MultipartReader(config UploadConfig) (*UploadReader, error) {
+	mediaType, params, err := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "multipart/form-data" && mediaType != "multipart/mixed") {
+		return nil, http.ErrNotMultipart
+	}
+	boundary, ok := params["boundary"]
+	if !ok {
+		return nil, http.ErrMissingBoundary
+	}
+
+	body := &countingReader{reader: c.Request.Body, limit: config.MaxTotalSize}
+	return &UploadReader{config: config, body: body, reader: multipart.NewReader(body, boundary)}, nil
+}
+
+// SaveUploadedFile 将上传的文件保存到 dst，目录不存在时自动创建
+// dst 中的文件名应使用 SanitizeFilename 清理，不要直接使用客户端提交的文件名
+func (c *Context) SaveUploadedFile(file *multipart.FileHeader, dst string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return saveFile(src, dst)
+}
+
+// NextPart 返回下一个 part，读取完成后返回 io.EOF
+// 上一个 part 未读取的数据会被丢弃
+func (r *UploadReader) NextPart() (*UploadPart, error) {
+	if r.part != nil {
+		// 丢弃上一个 part 剩余的数据，同样受到大小限制
+		if _, err := io.Copy(io.Discard, r.part); err != nil && !errors.Is(err, ErrFileTooLarge) {
+			return nil, err
+		}
+		r.part = nil
+	}
+
+	part, err := r.reader.NextPart()
+	if r.body.exceeded {
+		return nil, ErrUploadTooLarge
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &UploadPart{
+		FormName:    part.FormName(),
+		RawFileName: part.FileName(),
+		Header:      part.Header,
+		reader:      r,
+		buf:         bufio.NewReaderSize(part, sniffLen),
+	}
+	r.part = p
+	if p.RawFileName == "" {
+		return p, nil
+	}
+
+	p.FileName = SanitizeFilename(p.RawFileName)
+	// Peek 不会消费数据，识别类型之后仍然可以读取完整的文件内容
+	head, err := p.buf.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, r.error(err)
+	}
+	p.ContentType = http.DetectContentType(head)
+	if !r.allowed(p.ContentType) {
+		return nil, ErrFileTypeNotAllowed
+	}
+	return p, nil
+}
+
+func (r *UploadReader) allowed(contentType string) bool {
+	if len(r.config.AllowedTypes) == 0 {
+		return true
+	}
+	// 去掉 charset 等参数
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	for _, t := range r.config.AllowedTypes {
+		if strings.EqualFold(strings.TrimSpace(mediaType), t) {
+			return true
+		}
+	}
+	return false
+}
+
+// error 请求 body 超出限制时返回 ErrUploadTooLarge
+func (r *UploadReader) error(err error) error {
+	if r.body.exceeded {
+		return ErrUploadTooLarge
+	}
+	return err
+}
+
+// IsFile part 是否为文件
+func (p *UploadPart) IsFile() bool {
+	return p.RawFileName != ""
+}
+
+// Read 读取 part 的内容，文件超出 MaxFileSize 时返回 ErrFileTooLarge
+func (p *UploadPart) Read(data []byte) (int, error) {
+	limit := p.reader.config.MaxFileSize
+	if limit > 0 && p.IsFile() && p.size > limit {
+		return 0, ErrFileTooLarge
+	}
+	if limit > 0 && p.IsFile() && p.size+int64(len(data)) > limit+1 {
+		// 多读一个字节用于判断是否超出限制
+		data = data[:limit+1-p.size]
+	}
+
+	n, err := p.buf.Read(data)
+	p.size += int64(n)
+	if limit > 0 && p.IsFile() && p.size > limit {
+		return n - int(p.size-limit), ErrFileTooLarge
+	}
+	if err != nil && err != io.EOF {
+		return n, p.reader.error(err)
+	}
+	return n, err
+}
+
+// SaveTo 将文件保存到 dir 目录中，文件名为 FileName，返回文件路径
+// 保存失败时删除已经写入的文件
+func (p *UploadPart) SaveTo(dir string) (string, error) {
+	if !p.IsFile() {
+		return "", http.ErrMissingFile
+	}
+	dst := filepath.Join(dir, p.FileName)
+	return dst, saveFile(p, dst)
+}
+
+// saveFile 将 src 写入到 dst 中，写入失败时删除 dst
+func saveFile(src io.Reader, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, src)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+	}
+	return err
+}
+
+// SanitizeFilename 清理客户端提交的文件名，使其可以安全地作为本地文件名
+//  1. 去掉路径，只保留最后一部分，防止 ../ 等路径穿越
+//  2. 去掉控制字符和 Windows 不允许的字符 <>:"/\|?*
+//  3. 去掉首尾的空格和 .，Windows 保留名称（例如 CON、NUL）添加 _ 前缀
+//  4. 长度超过 255 字节时截断，尽量保留扩展名
+//
+// 清理后为空时返回 file
+func SanitizeFilename(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || unicode.IsControl(r) || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return "file"
+	}
+
+	base := name
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[:i]
+	}
+	if isWindowsReservedName(base) {
+		name = "_" + name
+	}
+
+	if len(name) > maxFilenameLength {
+		ext := filepath.Ext(name)
+		if len(ext) > maxFilenameLength/2 {
+			ext = ""
+		}
+		name = truncateUTF8(name[:len(name)-len(ext)], maxFilenameLength-len(ext)) + ext
+	}
+	return name
+}
+
+func isWindowsReservedName(name string) bool {
+	switch strings.ToUpper(name) {
+	case "CON", "PRN", "AUX", "NUL",
+		"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+		"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9":
+		return true
+	}
+	return false
+}
+
+// truncateUTF8 截断到 n 字节，不截断多字节字符
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// countingReader 统计读取的字节数，超出 limit 时返回 ErrUploadTooLarge，limit 为 0 表示不限制
+type countingReader struct {
+	reader   io.Reader
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func (r *countingReader) Read(data []byte) (int, error) {
+	if r.exceeded {
+		return 0, ErrUploadTooLarge
+	}
+	n, err := r.reader.Read(data)
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		r.exceeded = true
+		return n - int(r.read-r.limit), ErrUploadTooLarge
+	}
+	return n, err
+}
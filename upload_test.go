@@ -0,0 +1,226 @@
+package web
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+type uploadFile struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+func newUploadRequest(t *testing.T, fields map[string]string, files ...uploadFile) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, val := range fields {
+		require.NoError(t, writer.WriteField(key, val))
+	}
+	for _, f := range files {
+		part, err := writer.CreateFormFile(f.field, f.filename)
+		require.NoError(t, err)
+		_, err = part.Write(f.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	request := httptest.NewRequest(http.MethodPost, "/upload", body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func TestContext_SaveUploadedFile(t *testing.T) {
+	dir := t.TempDir()
+	server := New()
+	server.POST("/upload", func(ctx *Context) {
+		file, err := ctx.FormFile("avatar")
+		if err != nil {
+			ctx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		form, err := ctx.MultipartForm()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ray"}, form.Value["name"])
+
+		dst := filepath.Join(dir, "avatars", SanitizeFilename(file.Filename))
+		if err = ctx.SaveUploadedFile(file, dst); err != nil {
+			ctx.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		ctx.String(http.StatusOK, filepath.Base(dst))
+	})
+
+	request := newUploadRequest(t, map[string]string{"name": "ray"},
+		uploadFile{field: "avatar", filename: "../../avatar.png", content: pngHeader})
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "avatar.png", response.Body.String())
+	data, err := os.ReadFile(filepath.Join(dir, "avatars", "avatar.png"))
+	require.NoError(t, err)
+	assert.Equal(t, pngHeader, data)
+
+	request = newUploadRequest(t, map[string]string{"name": "ray"})
+	response = httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, http.ErrMissingFile.Error(), response.Body.String())
+}
+
+func TestContext_MultipartReader(t *testing.T) {
+	png := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 600)...)
+
+	testCases := []struct {
+		name      string
+		config    UploadConfig
+		request   func(t *testing.T) *http.Request
+		wantParts []string
+		wantFiles map[string][]byte
+		wantErr   error
+	}{
+		{
+			name: "stream to disk",
+			request: func(t *testing.T) *http.Request {
+				return newUploadRequest(t, map[string]string{"name": "ray"},
+					uploadFile{field: "avatar", filename: `C:\Users\ray\avatar.png`, content: png},
+					uploadFile{field: "doc", filename: "readme.txt", content: []byte("hello")})
+			},
+			wantParts: []string{"name", "avatar:avatar.png:image/png", "doc:readme.txt:text/plain; charset=utf-8"},
+			wantFiles: map[string][]byte{"avatar.png": png, "readme.txt": []byte("hello")},
+		},
+		{
+			name:   "allowed types",
+			config: UploadConfig{AllowedTypes: []string{"image/png"}},
+			request: func(t *testing.T) *http.Request {
+				return newUploadRequest(t, nil,
+					uploadFile{field: "avatar", filename: "avatar.png", content: png},
+					uploadFile{field: "avatar", filename: "fake.png", content: []byte("<html></html>")})
+			},
+			wantParts: []string{"avatar:avatar.png:image/png"},
+			wantFiles: map[string][]byte{"avatar.png": png},
+			wantErr:   ErrFileTypeNotAllowed,
+		},
+		{
+			name:   "file too large",
+			config: UploadConfig{MaxFileSize: 8},
+			request: func(t *testing.T) *http.Request {
+				return newUploadRequest(t, nil,
+					uploadFile{field: "doc", filename: "small.txt", content: []byte("12345678")},
+					uploadFile{field: "doc", filename: "large.txt", content: []byte("123456789")})
+			},
+			wantParts: []string{"doc:small.txt:text/plain; charset=utf-8", "doc:large.txt:text/plain; charset=utf-8"},
+			wantFiles: map[string][]byte{"small.txt": []byte("12345678")},
+			wantErr:   ErrFileTooLarge,
+		},
+		{
+			name:   "upload too large",
+			config: UploadConfig{MaxTotalSize: 8 << 10},
+			request: func(t *testing.T) *http.Request {
+				return newUploadRequest(t, nil,
+					uploadFile{field: "doc", filename: "large.txt", content: bytes.Repeat([]byte("a"), 16<<10)})
+			},
+			wantParts: []string{"doc:large.txt:text/plain; charset=utf-8"},
+			wantErr:   ErrUploadTooLarge,
+		},
+		{
+			name: "not multipart",
+			request: func(t *testing.T) *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("name=ray"))
+				request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return request
+			},
+			wantErr: http.ErrNotMultipart,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var (
+				parts []string
+				err   error
+			)
+			server := New()
+			server.POST("/upload", func(ctx *Context) {
+				var reader *UploadReader
+				if reader, err = ctx.MultipartReader(tt.config); err != nil {
+					return
+				}
+				for {
+					var part *UploadPart
+					if part, err = reader.NextPart(); err != nil {
+						if err == io.EOF {
+							err = nil
+						}
+						return
+					}
+					if !part.IsFile() {
+						parts = append(parts, part.FormName)
+						continue
+					}
+					parts = append(parts, part.FormName+":"+part.FileName+":"+part.ContentType)
+					if _, err = part.SaveTo(dir); err != nil {
+						return
+					}
+				}
+			})
+			server.ServeHTTP(httptest.NewRecorder(), tt.request(t))
+
+			assert.True(t, errors.Is(err, tt.wantErr), "%v", err)
+			assert.Equal(t, tt.wantParts, parts)
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			files := make(map[string][]byte, len(entries))
+			for _, entry := range entries {
+				data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoError(t, err)
+				files[entry.Name()] = data
+			}
+			if len(tt.wantFiles) == 0 {
+				assert.Empty(t, files)
+				return
+			}
+			assert.Equal(t, tt.wantFiles, files)
+		})
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		want     string
+	}{
+		{name: "plain", filename: "avatar.png", want: "avatar.png"},
+		{name: "unix path", filename: "../../etc/passwd", want: "passwd"},
+		{name: "windows path", filename: `..\..\boot.ini`, want: "boot.ini"},
+		{name: "reserved characters", filename: `a<b>c:d"e|f?g*.txt`, want: "abcdefg.txt"},
+		{name: "control characters", filename: "a\x00b\nc.txt", want: "abc.txt"},
+		{name: "dots and spaces", filename: " .. ", want: "file"},
+		{name: "hidden", filename: ".env", want: "env"},
+		{name: "windows reserved", filename: "con.txt", want: "_con.txt"},
+		{name: "unicode", filename: "头像.png", want: "头像.png"},
+		{name: "too long", filename: strings.Repeat("头", 100) + ".png", want: strings.Repeat("头", 83) + ".png"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeFilename(tt.filename)
+			assert.Equal(t, tt.want, got)
+			assert.LessOrEqual(t, len(got), maxFilenameLength)
+		})
+	}
+}
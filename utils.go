@@ -3,6 +3,8 @@ package web
 import (
 	"net/http"
 	"path"
+	"strings"
+	"unicode/utf8"
 )
 
 var (
@@ -45,3 +47,73 @@ func lastChar(str string) uint8 {
 	}
 	return str[len(str)-1]
 }
+
+// bodyAllowedForStatus 校验状态码是否允许携带响应 body，参考 http.bodyAllowedForStatus
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent:
+		return false
+	case status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
+
+// contentDisposition 生成 Content-Disposition 响应头，参考 RFC 6266
+// filename 为 ASCII 文件名，非 ASCII 字符替换为 _，filename* 为 RFC 5987 编码的 UTF-8 文件名
+func contentDisposition(dispositionType, filename string) string {
+	ascii, isASCII := asciiFilename(filename)
+	value := dispositionType + `; filename="` + ascii + `"`
+	if !isASCII {
+		value += "; filename*=UTF-8''" + encodeRFC5987(filename)
+	}
+	return value
+}
+
+// asciiFilename 返回可以放在双引号中的 ASCII 文件名，转义 " 和 \，替换控制字符和非 ASCII 字符
+func asciiFilename(filename string) (string, bool) {
+	var sb strings.Builder
+	isASCII := true
+	for _, r := range filename {
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			sb.WriteByte('_')
+		case r >= utf8.RuneSelf:
+			isASCII = false
+			sb.WriteByte('_')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String(), isASCII
+}
+
+// encodeRFC5987 按照 RFC 5987 对值进行百分号编码，只保留 attr-char
+func encodeRFC5987(value string) string {
+	const hex = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		if isAttrChar(b) {
+			sb.WriteByte(b)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(hex[b>>4])
+		sb.WriteByte(hex[b&0x0f])
+	}
+	return sb.String()
+}
+
+func isAttrChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) >= 0
+}
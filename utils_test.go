@@ -42,3 +42,37 @@ func Test_joinPaths(t *testing.T) {
 		})
 	}
 }
+
+func Test_contentDisposition(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		want     string
+	}{
+		{
+			name:     "ascii",
+			filename: "report.pdf",
+			want:     `attachment; filename="report.pdf"`,
+		},
+		{
+			name:     "quote and backslash",
+			filename: `a"b\c.txt`,
+			want:     `attachment; filename="a\"b\\c.txt"`,
+		},
+		{
+			name:     "control character",
+			filename: "a\r\nb.txt",
+			want:     `attachment; filename="a__b.txt"`,
+		},
+		{
+			name:     "utf-8",
+			filename: "报告 2023.pdf",
+			want:     `attachment; filename="__ 2023.pdf"; filename*=UTF-8''%E6%8A%A5%E5%91%8A%202023.pdf`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, contentDisposition("attachment", tt.filename))
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+var _ context.Context = (*Context)(nil)
+
+// Deadline 返回请求的截止时间，委托给 http.Request.Context
+func (c *Context) Deadline() (deadline time.Time, ok bool) {
+	if c.Request == nil {
+		return
+	}
+	return c.Request.Context().Deadline()
+}
+
+// Done 请求被取消或者超时后关闭，例如客户端断开连接
+func (c *Context) Done() <-chan struct{} {
+	if c.Request == nil {
+		return nil
+	}
+	return c.Request.Context().Done()
+}
+
+// Err 返回请求被取消的原因，未取消时返回 nil
+func (c *Context) Err() error {
+	if c.Request == nil {
+		return nil
+	}
+	return c.Request.Context().Err()
+}
+
+// Value 优先查找使用 Set 设置的值，key 为 string 类型时生效，其次查找 http.Request.Context
+func (c *Context) Value(key any) any {
+	if k, ok := key.(string); ok {
+		if val, exists := c.Get(k); exists {
+			return val
+		}
+	}
+	if c.Request == nil {
+		return nil
+	}
+	return c.Request.Context().Value(key)
+}
+
+// Set 保存请求范围内的值，并发安全，Context 归还到 Pool 时清空
+func (c *Context) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.UserValues == nil {
+		c.UserValues = make(map[string]any, 4)
+	}
+	c.UserValues[key] = value
+}
+
+// Get 获取使用 Set 保存的值
+func (c *Context) Get(key string) (value any, exists bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, exists = c.UserValues[key]
+	return
+}
+
+// MustGet 获取使用 Set 保存的值，不存在时 panic
+func (c *Context) MustGet(key string) any {
+	if value, exists := c.Get(key); exists {
+		return value
+	}
+	panic(fmt.Sprintf("web：key %s 不存在", key))
+}
+
+// GetString 获取 string 类型的值，不存在或者类型不匹配时返回零值，其余 GetXxx 方法相同
+func (c *Context) GetString(key string) string {
+	val, _ := Value[string](c, key)
+	return val
+}
+
+func (c *Context) GetBool(key string) bool {
+	val, _ := Value[bool](c, key)
+	return val
+}
+
+func (c *Context) GetInt(key string) int {
+	val, _ := Value[int](c, key)
+	return val
+}
+
+func (c *Context) GetInt64(key string) int64 {
+	val, _ := Value[int64](c, key)
+	return val
+}
+
+func (c *Context) GetUint(key string) uint {
+	val, _ := Value[uint](c, key)
+	return val
+}
+
+func (c *Context) GetFloat64(key string) float64 {
+	val, _ := Value[float64](c, key)
+	return val
+}
+
+func (c *Context) GetTime(key string) time.Time {
+	val, _ := Value[time.Time](c, key)
+	return val
+}
+
+func (c *Context) GetDuration(key string) time.Duration {
+	val, _ := Value[time.Duration](c, key)
+	return val
+}
+
+func (c *Context) GetStringSlice(key string) []string {
+	val, _ := Value[[]string](c, key)
+	return val
+}
+
+func (c *Context) GetStringMap(key string) map[string]any {
+	val, _ := Value[map[string]any](c, key)
+	return val
+}
+
+// Value 获取使用 Set 保存的 T 类型的值，不存在或者类型不匹配时返回零值和 false
+//
+//	user, ok := web.Value[*User](ctx, "user")
+func Value[T any](c *Context, key string) (T, bool) {
+	var zero T
+	val, exists := c.Get(key)
+	if !exists {
+		return zero, false
+	}
+	res, ok := val.(T)
+	if !ok {
+		return zero, false
+	}
+	return res, true
+}
+
+// Copy 返回当前 Context 的只读快照，包括请求、路由参数、路由和请求范围内的值
+// Context 会被 Pool 复用，传递给 goroutine 或者在处理器返回后使用时必须使用副本
+// 副本没有 Writer，不能用于写回响应
+func (c *Context) Copy() *Context {
+	cp := &Context{
+		Request:    c.Request,
+		Params:     make(Params, len(c.Params)),
+		Route:      c.Route,
+		RespStatus: c.RespStatus,
+		h:          c.h,
+	}
+	copy(cp.Params, c.Params)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.UserValues != nil {
+		cp.UserValues = make(map[string]any, len(c.UserValues))
+		for k, v := range c.UserValues {
+			cp.UserValues[k] = v
+		}
+	}
+	return cp
+}
@@ -0,0 +1,158 @@
+package web
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+func TestContext_Values(t *testing.T) {
+	now := time.Now()
+	ctx := &Context{}
+	ctx.Set("string", "ray")
+	ctx.Set("bool", true)
+	ctx.Set("int", 18)
+	ctx.Set("int64", int64(64))
+	ctx.Set("uint", uint(8))
+	ctx.Set("float64", 1.5)
+	ctx.Set("time", now)
+	ctx.Set("duration", time.Second)
+	ctx.Set("slice", []string{"a", "b"})
+	ctx.Set("map", map[string]any{"a": 1})
+	ctx.Set("user", &User{Name: "ray"})
+
+	assert.Equal(t, "ray", ctx.GetString("string"))
+	assert.True(t, ctx.GetBool("bool"))
+	assert.Equal(t, 18, ctx.GetInt("int"))
+	assert.Equal(t, int64(64), ctx.GetInt64("int64"))
+	assert.Equal(t, uint(8), ctx.GetUint("uint"))
+	assert.Equal(t, 1.5, ctx.GetFloat64("float64"))
+	assert.Equal(t, now, ctx.GetTime("time"))
+	assert.Equal(t, time.Second, ctx.GetDuration("duration"))
+	assert.Equal(t, []string{"a", "b"}, ctx.GetStringSlice("slice"))
+	assert.Equal(t, map[string]any{"a": 1}, ctx.GetStringMap("map"))
+
+	// 类型不匹配和不存在时返回零值
+	assert.Equal(t, "", ctx.GetString("int"))
+	assert.Equal(t, 0, ctx.GetInt("missing"))
+
+	user, ok := Value[*User](ctx, "user")
+	assert.True(t, ok)
+	assert.Equal(t, "ray", user.Name)
+	_, ok = Value[User](ctx, "user")
+	assert.False(t, ok)
+	_, ok = Value[*User](ctx, "missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, 18, ctx.MustGet("int"))
+	assert.PanicsWithValue(t, "web：key missing 不存在", func() {
+		ctx.MustGet("missing")
+	})
+}
+
+func TestContext_ContextContract(t *testing.T) {
+	server := New()
+	var (
+		values  []any
+		reqCtx  context.Context
+		pooled  *Context
+		cancel  context.CancelFunc
+		errDone error
+	)
+	server.GET("/", func(ctx *Context) {
+		ctx.Set("user", "ray")
+		values = []any{ctx.Value("user"), ctx.Value(ctxKey{}), ctx.Value("missing")}
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.False(t, deadline.IsZero())
+		assert.NoError(t, ctx.Err())
+
+		// 作为 context.Context 传递给下游
+		reqCtx = ctx
+		cancel()
+		<-ctx.Done()
+		errDone = ctx.Err()
+		pooled = ctx
+	})
+
+	base, cancelDeadline := context.WithTimeout(context.WithValue(context.Background(), ctxKey{}, "request"), time.Minute)
+	defer cancelDeadline()
+	base, cancel = context.WithCancel(base)
+	defer cancel()
+	request := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(base)
+	server.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, []any{"ray", "request", nil}, values)
+	assert.NotNil(t, reqCtx)
+	assert.Equal(t, context.Canceled, errDone)
+
+	// 归还到 Pool 后请求范围内的值被清空
+	_, exists := pooled.Get("user")
+	assert.False(t, exists)
+	assert.Nil(t, pooled.Request)
+
+	// 没有 Request 时不会 panic
+	empty := &Context{}
+	assert.Nil(t, empty.Done())
+	assert.NoError(t, empty.Err())
+	assert.Nil(t, empty.Value(ctxKey{}))
+}
+
+func TestContext_Copy(t *testing.T) {
+	server := New()
+	const requests = 64
+
+	type snapshot struct {
+		want string
+		got  []string
+	}
+	results := make(chan snapshot, requests)
+	release := make(chan struct{})
+	server.GET("/user/:id", func(ctx *Context) {
+		ctx.Set("id", ctx.Param("id"))
+		cp := ctx.Copy()
+		// 副本在处理器返回、原 Context 被复用之后才使用
+		go func() {
+			<-release
+			results <- snapshot{
+				want: cp.Query("id"),
+				got:  []string{cp.Param("id"), cp.GetString("id"), cp.Route, cp.Request.URL.Path},
+			}
+		}()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := strconv.Itoa(i)
+			request := httptest.NewRequest(http.MethodGet, "/user/"+id+"?id="+id, nil)
+			server.ServeHTTP(httptest.NewRecorder(), request)
+		}(i)
+	}
+	wg.Wait()
+	close(release)
+
+	for i := 0; i < requests; i++ {
+		res := <-results
+		assert.Equal(t, []string{res.want, res.want, "/user/:id", "/user/" + res.want}, res.got)
+	}
+
+	// 修改副本不影响原 Context
+	ctx := &Context{Params: Params{{key: "id", value: "1"}}}
+	ctx.Set("user", "ray")
+	cp := ctx.Copy()
+	cp.Set("user", "tom")
+	cp.Params[0].value = "2"
+	assert.Equal(t, "ray", ctx.GetString("user"))
+	assert.Equal(t, "1", ctx.Param("id"))
+	assert.Nil(t, cp.Writer)
+}
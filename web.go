@@ -1,10 +1,15 @@
 package web
 
 import (
+	"github.com/killlowkey/web/binding"
+	"github.com/killlowkey/web/render"
 	"html/template"
+	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"sync"
+	"time"
 )
 
 // HandleFunc 请求处理器
@@ -23,7 +28,41 @@ type HttpServer struct {
 
 	middlewares []Middleware
 
-	templ *template.Template
+	// HTMLRender Context.HTML 使用的模版引擎，可以替换为其它实现
+	HTMLRender render.HTMLRender
+	// DebugMode 调试模式，开启后 HTML 模版文件修改会自动重新加载，需要在加载模版前设置
+	DebugMode bool
+
+	funcMap template.FuncMap
+	delims  render.Delims
+
+	// MaxBodyBytes 请求 body 的最大字节数，0 表示不限制，超出限制响应 413
+	// 使用 BodyLimit 可以为 RouterGroup 单独配置
+	MaxBodyBytes int64
+	// RequestTimeout 处理请求的超时时间，0 表示不限制，超时响应 503
+	// 使用 Timeout 或者 NewTimeoutMiddlewareBuild 可以为 RouterGroup 和单个路由单独配置
+	RequestTimeout time.Duration
+	// JSONOptions Context.BindJSON 解析请求 body 的选项
+	JSONOptions binding.JSONOptions
+	// SecureJSONPrefix Context.SecureJSON 响应数组时添加的前缀，防止 JSON 劫持
+	SecureJSONPrefix string
+	// CookieOptions Context.SetCookie 使用的默认属性
+	CookieOptions CookieOptions
+	// CookieKeys 签名和加密 Cookie 的密钥，第一个密钥用于生成 Cookie，所有密钥都用于校验
+	// 轮换密钥时将新密钥放在第一个，旧密钥保留到使用旧密钥生成的 Cookie 全部过期
+	CookieKeys [][]byte
+
+	// RemoteIPHeaders Context.ClientIP 解析客户端 IP 的请求头，按照顺序使用第一个有效的请求头
+	// 只有请求来自可信代理时才会解析，支持 X-Forwarded-For、X-Real-IP 和 RFC 7239 Forwarded
+	RemoteIPHeaders []string
+	// TrustedPlatform 云平台设置的客户端 IP 请求头，例如 PlatformCloudflare，优先级最高
+	TrustedPlatform string
+	// RedirectAllowedHosts Context.Redirect 允许重定向的站点，支持 *.example.com，当前请求的站点总是允许的
+	// 不为 nil 时开启校验，重定向到其它站点会被拒绝，防止开放重定向
+	RedirectAllowedHosts []string
+
+	// trustedCIDRs 可信代理，使用 SetTrustedProxies 设置
+	trustedCIDRs []*net.IPNet
 }
 
 func New() *HttpServer {
@@ -36,6 +75,13 @@ func New() *HttpServer {
 		trees: &Trees{
 			trees: make(map[string]*Tree, 9),
 		},
+		SecureJSONPrefix: "while(1);",
+		RemoteIPHeaders:  []string{"X-Forwarded-For", "X-Real-IP"},
+		CookieOptions: CookieOptions{
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
 	}
 	s.RouterGroup.server = s
 	s.pool.New = func() any {
@@ -71,6 +117,13 @@ func (h *HttpServer) ServeHTTP(writer http.ResponseWriter, request *http.Request
 	flush := func(next HandleFunc) HandleFunc {
 		return func(ctx *Context) {
 			defer func() {
+				// trailer 在 body 之后写回
+				defer ctx.writeTrailers()
+				// 响应已经直接写入，例如流式渲染的模版
+				if ctx.written {
+					return
+				}
+				ctx.runBeforeWrite()
 				ctx.Writer.WriteHeader(ctx.RespStatus)
 				if _, err := ctx.Writer.Write(ctx.RespData); err != nil {
 					// TODO 将就用，打条日志出来就完事了
@@ -83,13 +136,22 @@ func (h *HttpServer) ServeHTTP(writer http.ResponseWriter, request *http.Request
 
 	// 组合全局 middleware
 	root := h.handleHttpRequest
-	middlewares := append([]Middleware{flush}, h.middlewares...)
+	middlewares := []Middleware{flush}
+	// Timeout 在 BodyLimit 外层，超出限制的 413 由处理器的副本生成并合并
+	if h.RequestTimeout > 0 {
+		middlewares = append(middlewares, Timeout(h.RequestTimeout))
+	}
+	if h.MaxBodyBytes > 0 {
+		middlewares = append(middlewares, BodyLimit(h.MaxBodyBytes))
+	}
+	middlewares = append(middlewares, h.middlewares...)
 	for i := len(middlewares) - 1; i >= 0; i-- {
 		root = middlewares[i](root)
 	}
 	root(ctx)
 
 	// 归还 context
+	ctx.reset()
 	h.pool.Put(ctx)
 }
 
@@ -139,6 +201,59 @@ func (h *HttpServer) RunTLS(addr, certFile, keyFile string) error {
 	return http.ListenAndServeTLS(addr, certFile, keyFile, h)
 }
 
+// Delims 设置 HTML 模版的左右分隔符，需要在加载模版前设置
+func (h *HttpServer) Delims(left, right string) {
+	h.delims = render.Delims{Left: left, Right: right}
+}
+
+// SetFuncMap 设置 HTML 模版使用的函数，需要在加载模版前设置
+func (h *HttpServer) SetFuncMap(funcMap template.FuncMap) {
+	h.funcMap = funcMap
+}
+
+// SetHTMLTemplate 使用已经解析好的模版
+func (h *HttpServer) SetHTMLTemplate(templ *template.Template) {
+	h.HTMLRender = render.HTMLProduction{Template: templ}
+}
+
+// LoadHTMLFiles 加载本地的模版文件
 func (h *HttpServer) LoadHTMLFiles(files ...string) {
-	h.templ = template.Must(template.New("").Delims("{{", "}}").ParseFiles(files...))
+	h.LoadHTML(render.HTMLLoader{Patterns: files})
+}
+
+// LoadHTMLGlob 加载本地匹配 pattern 的模版文件，例如 templates/*.tmpl
+func (h *HttpServer) LoadHTMLGlob(pattern string) {
+	h.LoadHTML(render.HTMLLoader{Patterns: []string{pattern}})
+}
+
+// LoadHTMLFS 从 fs.FS 加载模版，可以使用 embed.FS 将模版打包到二进制文件中
+func (h *HttpServer) LoadHTMLFS(fsys fs.FS, patterns ...string) {
+	h.LoadHTML(render.HTMLLoader{FS: fsys, Patterns: patterns})
+}
+
+// LoadHTML 根据配置加载模版，未设置 FuncMap 和 Delims 时使用 HttpServer 的配置
+// 调试模式下模版文件修改后会自动重新加载，否则只加载一次，加载失败 panic
+func (h *HttpServer) LoadHTML(loader render.HTMLLoader) {
+	if loader.FuncMap == nil {
+		loader.FuncMap = h.funcMap
+	}
+	if loader.Delims == (render.Delims{}) {
+		loader.Delims = h.delims
+	}
+
+	if h.DebugMode {
+		debug := &render.HTMLDebug{Loader: loader}
+		// 启动时加载一次，提前暴露模版错误
+		if err := debug.Reload(); err != nil {
+			panic(err)
+		}
+		h.HTMLRender = debug
+		return
+	}
+
+	htmlRender, err := loader.Load()
+	if err != nil {
+		panic(err)
+	}
+	h.HTMLRender = htmlRender
 }
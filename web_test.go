@@ -2,10 +2,19 @@ package web
 
 import (
 	"fmt"
+	"github.com/killlowkey/web/render"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"html/template"
 	"log"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 type User struct {
@@ -119,3 +128,156 @@ func TestContext_File(t *testing.T) {
 	})
 	_ = server.Run(":8080")
 }
+
+func TestHttpServer_LoadHTML(t *testing.T) {
+	templates := fstest.MapFS{
+		"views/layout.tmpl":      {Data: []byte(`{{define "layout"}}<html>{{block "content" .}}default{{end}}{{template "footer"}}</html>{{end}}`)},
+		"views/footer.tmpl":      {Data: []byte(`{{define "footer"}}<footer>{{year}}</footer>{{end}}`)},
+		"views/index.tmpl":       {Data: []byte(`{{define "content"}}index {{.name}}{{end}}`)},
+		"views/about.tmpl":       {Data: []byte(`{{define "content"}}about {{.name}}{{end}}`)},
+		"views/admin/index.tmpl": {Data: []byte(`{{define "content"}}admin {{.name}}{{end}}`)},
+		"views/user/index.tmpl":  {Data: []byte(`{{define "content"}}user {{.name}}{{end}}`)},
+		"pages/hello.tmpl":       {Data: []byte(`<p>[[ upper .name ]]</p>`)},
+		"pages/goodbye.tmpl":     {Data: []byte(`<p>bye [[ .name ]]</p>`)},
+	}
+	funcMap := template.FuncMap{
+		"year":  func() int { return 2023 },
+		"upper": strings.ToUpper,
+	}
+
+	testCases := []struct {
+		name     string
+		load     func(server *HttpServer)
+		tmpl     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "glob",
+			load: func(server *HttpServer) {
+				server.LoadHTMLGlob("testData/*.tmpl")
+			},
+			tmpl:     "hello.tmpl",
+			wantCode: http.StatusOK,
+			wantBody: "<h1>hello ray</h1>\n",
+		},
+		{
+			name: "fs with func map and delims",
+			load: func(server *HttpServer) {
+				server.Delims("[[", "]]")
+				server.SetFuncMap(funcMap)
+				server.LoadHTMLFS(templates, "pages/*.tmpl")
+			},
+			tmpl:     "hello.tmpl",
+			wantCode: http.StatusOK,
+			wantBody: "<p>RAY</p>",
+		},
+		{
+			name: "layout",
+			load: func(server *HttpServer) {
+				server.SetFuncMap(funcMap)
+				server.LoadHTML(render.HTMLLoader{
+					FS:       templates,
+					Patterns: []string{"views/*.tmpl"},
+					Layout:   "layout",
+					Shared:   []string{"views/layout.tmpl", "views/footer.tmpl"},
+				})
+			},
+			tmpl:     "about.tmpl",
+			wantCode: http.StatusOK,
+			wantBody: "<html>about ray<footer>2023</footer></html>",
+		},
+		{
+			name: "layout same name in different directories",
+			load: func(server *HttpServer) {
+				server.SetFuncMap(funcMap)
+				server.LoadHTML(render.HTMLLoader{
+					FS:       templates,
+					Patterns: []string{"views/*/*.tmpl"},
+					Layout:   "layout",
+					Shared:   []string{"views/layout.tmpl", "views/footer.tmpl"},
+				})
+			},
+			tmpl:     "user/index.tmpl",
+			wantCode: http.StatusOK,
+			wantBody: "<html>user ray<footer>2023</footer></html>",
+		},
+		{
+			name: "layout page not found",
+			load: func(server *HttpServer) {
+				server.SetFuncMap(funcMap)
+				server.LoadHTML(render.HTMLLoader{
+					FS:       templates,
+					Patterns: []string{"views/index.tmpl"},
+					Layout:   "layout",
+					Shared:   []string{"views/layout.tmpl", "views/footer.tmpl"},
+				})
+			},
+			tmpl:     "about.tmpl",
+			wantCode: http.StatusInternalServerError,
+			wantBody: internalError,
+		},
+		{
+			name:     "not loaded",
+			load:     func(server *HttpServer) {},
+			tmpl:     "hello.tmpl",
+			wantCode: http.StatusInternalServerError,
+			wantBody: internalError,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := New()
+			tt.load(server)
+			server.GET("/", func(ctx *Context) {
+				ctx.HTML(http.StatusOK, tt.tmpl, H{"name": "ray"})
+			})
+			request, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantBody, response.Body.String())
+		})
+	}
+
+	assert.Panics(t, func() {
+		New().LoadHTMLGlob("testData/*.html")
+	})
+
+	// 不同目录中的同名页面不能互相覆盖
+	_, err := render.HTMLLoader{
+		FS:       templates,
+		Patterns: []string{"views/admin/*.tmpl", "views/user/*.tmpl"},
+		Layout:   "layout",
+		Shared:   []string{"views/layout.tmpl", "views/footer.tmpl"},
+		FuncMap:  funcMap,
+	}.Load()
+	assert.ErrorContains(t, err, "名称重复")
+}
+
+func TestHttpServer_LoadHTMLDebug(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "index.tmpl")
+	require.NoError(t, os.WriteFile(file, []byte("v1 {{.}}"), 0o644))
+
+	server := New()
+	server.DebugMode = true
+	server.LoadHTMLFiles(file)
+	server.GET("/", func(ctx *Context) {
+		ctx.HTML(http.StatusOK, "index.tmpl", "ray")
+	})
+	get := func() string {
+		request, err := http.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		return response.Body.String()
+	}
+
+	assert.Equal(t, "v1 ray", get())
+	require.NoError(t, os.WriteFile(file, []byte("v2 {{.}}"), 0o644))
+	// 保证修改时间发生变化
+	require.NoError(t, os.Chtimes(file, time.Now(), time.Now().Add(time.Second)))
+	assert.Equal(t, "v2 ray", get())
+}